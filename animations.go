@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/creativeprojects/cavern/lib"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AnimationName is the name of an animation defined in a file of the animations folder
+type AnimationName string
+
+// Animations defined in the animations folder
+const (
+	AnimationPlayerStill       AnimationName = "player-still"
+	AnimationPlayerFall        AnimationName = "player-fall"
+	AnimationOrbBlow           AnimationName = "orb-blow"
+	AnimationFruitApple        AnimationName = "fruit-apple"
+	AnimationFruitRaspberry    AnimationName = "fruit-raspberry"
+	AnimationFruitLemon        AnimationName = "fruit-lemon"
+	AnimationFruitExtraHealth  AnimationName = "fruit-extra-health"
+	AnimationFruitExtraLife    AnimationName = "fruit-extra-life"
+	AnimationPopFruit          AnimationName = "pop-fruit"
+	AnimationPopOrb            AnimationName = "pop-orb"
+	AnimationOrbTrapNormal     AnimationName = "orb-trap-normal"
+	AnimationOrbTrapAggressive AnimationName = "orb-trap-aggressive"
+)
+
+// Animations facing left and right (the left one first)
+var (
+	AnimationsPlayerRun    = [2]AnimationName{"player-run-left", "player-run-right"}
+	AnimationsPlayerJump   = [2]AnimationName{"player-jump-left", "player-jump-right"}
+	AnimationsPlayerBlow   = [2]AnimationName{"player-blow-left", "player-blow-right"}
+	AnimationsPlayerRecoil = [2]AnimationName{"player-recoil-left", "player-recoil-right"}
+	AnimationsBolt         = [2]AnimationName{"bolt-left", "bolt-right"}
+	// [robot type][direction]
+	AnimationsRobotWalk = [2][2]AnimationName{
+		{"robot-normal-walk-left", "robot-normal-walk-right"},
+		{"robot-aggressive-walk-left", "robot-aggressive-walk-right"},
+	}
+	AnimationsRobotFire = [2][2]AnimationName{
+		{"robot-normal-fire-left", "robot-normal-fire-right"},
+		{"robot-aggressive-fire-left", "robot-aggressive-fire-right"},
+	}
+)
+
+// Events declared on the frames of the animations
+const (
+	EventFire  = "fire"   // a robot releases its bolt
+	EventSound = "sound:" // prefix of the events playing a random sound of a group, like "sound:laser" (see SoundGroup)
+)
+
+// allAnimations lists all the animations used by the game
+var allAnimations = []AnimationName{
+	AnimationPlayerStill, AnimationPlayerFall,
+	AnimationsPlayerRun[0], AnimationsPlayerRun[1],
+	AnimationsPlayerJump[0], AnimationsPlayerJump[1],
+	AnimationsPlayerBlow[0], AnimationsPlayerBlow[1],
+	AnimationsPlayerRecoil[0], AnimationsPlayerRecoil[1],
+	AnimationsRobotWalk[0][0], AnimationsRobotWalk[0][1], AnimationsRobotWalk[1][0], AnimationsRobotWalk[1][1],
+	AnimationsRobotFire[0][0], AnimationsRobotFire[0][1], AnimationsRobotFire[1][0], AnimationsRobotFire[1][1],
+	AnimationOrbBlow, AnimationOrbTrapNormal, AnimationOrbTrapAggressive,
+	AnimationFruitApple, AnimationFruitRaspberry, AnimationFruitLemon, AnimationFruitExtraHealth, AnimationFruitExtraLife,
+	AnimationPopFruit, AnimationPopOrb,
+	AnimationsBolt[0], AnimationsBolt[1],
+}
+
+// Animation returns the loaded animation
+func Animation(name AnimationName) *lib.Animation {
+	return animations[string(name)]
+}
+
+// loadAnimations loads all the animation files (animations/*.json).
+// The frames are the names of the images already loaded.
+func loadAnimations(fsys fs.FS) (map[string]*lib.Animation, error) {
+	filenames, err := fs.Glob(fsys, "animations/*.json")
+	if err != nil {
+		return nil, err
+	}
+	lookup := func(name string) *ebiten.Image {
+		return images[name]
+	}
+	loaded := make(map[string]*lib.Animation, len(allAnimations))
+	for _, filename := range filenames {
+		file, err := fsys.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		list, err := lib.LoadAnimations(file, lookup)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		for name, animation := range list {
+			if _, found := loaded[name]; found {
+				return nil, fmt.Errorf("%s: animation %q is already defined in another file", filename, name)
+			}
+			loaded[name] = animation
+		}
+	}
+	return loaded, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEmbeddedAnimations(t *testing.T) {
+	previous := images
+	defer func() {
+		images = previous
+	}()
+	imageNames, err := fs.Glob(embededFiles, "images/*.png")
+	require.NoError(t, err)
+	images = make(map[string]*ebiten.Image, len(imageNames))
+	for _, imageName := range imageNames {
+		images[assetName(imageName)] = new(ebiten.Image)
+	}
+
+	loaded, err := loadAnimations(embededFiles)
+	require.NoError(t, err)
+	for _, name := range allAnimations {
+		assert.Contains(t, loaded, string(name))
+	}
+	for name, animation := range loaded {
+		for _, event := range animation.Events {
+			if group, found := strings.CutPrefix(event.Name, EventSound); found {
+				assert.NotEmpty(t, SoundGroup(group), "animation %q: no sound in group %q", name, group)
+			}
+		}
+	}
+}
+
+func TestSoundGroup(t *testing.T) {
+	assert.Equal(t, SoundsLaser[:], SoundGroup("laser"))
+	assert.Equal(t, []SoundName{SoundDie0}, SoundGroup("die"))
+	assert.Empty(t, SoundGroup("laser0"))
+}
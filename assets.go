@@ -0,0 +1,89 @@
+package main
+
+//go:generate go run ./cmd/assetgen -o assets_gen.go
+//go:generate go run ./cmd/fallbackfont -o fonts/fallback
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ImageName is the name of a file in the images folder, without the extension
+type ImageName string
+
+// SoundName is the name of a file in the sounds folder, without the extension
+type SoundName string
+
+// Image returns the loaded image
+func Image(name ImageName) *ebiten.Image {
+	return images[string(name)]
+}
+
+// Sound returns the loaded sound effect
+func Sound(name SoundName) []byte {
+	return sounds[string(name)]
+}
+
+// soundGroups lists the sounds by group (see SoundGroup)
+var soundGroups = groupSounds(allSounds)
+
+// SoundGroup returns the sounds named after the group followed by a number, like laser0 to laser3 for "laser"
+func SoundGroup(group string) []SoundName {
+	return soundGroups[group]
+}
+
+func groupSounds(names []SoundName) map[string][]SoundName {
+	groups := make(map[string][]SoundName)
+	for _, name := range names {
+		group := strings.TrimRight(string(name), "0123456789")
+		groups[group] = append(groups[group], name)
+	}
+	return groups
+}
+
+// validateAssets checks that all the assets known at compile time have been loaded.
+// It returns an error listing all the missing assets.
+func validateAssets() error {
+	missing := make([]string, 0)
+	for _, name := range allImages {
+		if _, found := images[string(name)]; !found {
+			missing = append(missing, "image "+string(name))
+		}
+	}
+	for _, name := range allSounds {
+		if _, found := sounds[string(name)]; !found {
+			missing = append(missing, "sound "+string(name))
+		}
+	}
+	for _, name := range allAnimations {
+		if _, found := animations[string(name)]; !found {
+			missing = append(missing, "animation "+string(name))
+		}
+	}
+	for _, name := range allShaders {
+		if _, found := shaders[name]; !found {
+			missing = append(missing, "shader "+name)
+		}
+	}
+	for _, name := range themePresets {
+		if _, found := themes[name]; !found {
+			missing = append(missing, "theme "+name)
+		}
+	}
+	for i, level := range LevelsDefinition {
+		if _, found := themes[level.Theme]; level.Theme != "" && !found {
+			missing = append(missing, fmt.Sprintf("theme %s (level %d)", level.Theme, i+1))
+		}
+	}
+	if _, err := fs.Stat(assetFiles, musicFile(musicDefault)); errors.Is(err, fs.ErrNotExist) {
+		missing = append(missing, "music "+musicDefault)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing assets (run go generate after adding or removing asset files):\n\t%s", strings.Join(missing, "\n\t"))
+	}
+	return nil
+}
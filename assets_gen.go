@@ -0,0 +1,369 @@
+// Code generated by go run ./cmd/assetgen; DO NOT EDIT.
+
+package main
+
+// Files in the images folder
+const (
+	ImageBg0      ImageName = "bg0"
+	ImageBg1      ImageName = "bg1"
+	ImageBg2      ImageName = "bg2"
+	ImageBg3      ImageName = "bg3"
+	ImageBlock0   ImageName = "block0"
+	ImageBlock1   ImageName = "block1"
+	ImageBlock2   ImageName = "block2"
+	ImageBlock3   ImageName = "block3"
+	ImageBlow0    ImageName = "blow0"
+	ImageBolt00   ImageName = "bolt00"
+	ImageBolt01   ImageName = "bolt01"
+	ImageBolt10   ImageName = "bolt10"
+	ImageBolt11   ImageName = "bolt11"
+	ImageCursor   ImageName = "cursor"
+	ImageFall0    ImageName = "fall0"
+	ImageFall1    ImageName = "fall1"
+	ImageFruit00  ImageName = "fruit00"
+	ImageFruit01  ImageName = "fruit01"
+	ImageFruit02  ImageName = "fruit02"
+	ImageFruit10  ImageName = "fruit10"
+	ImageFruit11  ImageName = "fruit11"
+	ImageFruit12  ImageName = "fruit12"
+	ImageFruit20  ImageName = "fruit20"
+	ImageFruit21  ImageName = "fruit21"
+	ImageFruit22  ImageName = "fruit22"
+	ImageFruit30  ImageName = "fruit30"
+	ImageFruit31  ImageName = "fruit31"
+	ImageFruit32  ImageName = "fruit32"
+	ImageFruit40  ImageName = "fruit40"
+	ImageFruit41  ImageName = "fruit41"
+	ImageFruit42  ImageName = "fruit42"
+	ImageHealth   ImageName = "health"
+	ImageJump0    ImageName = "jump0"
+	ImageLife     ImageName = "life"
+	ImageOrb0     ImageName = "orb0"
+	ImageOrb1     ImageName = "orb1"
+	ImageOrb2     ImageName = "orb2"
+	ImageOrb3     ImageName = "orb3"
+	ImageOrb4     ImageName = "orb4"
+	ImageOrb5     ImageName = "orb5"
+	ImageOrb6     ImageName = "orb6"
+	ImagePlus     ImageName = "plus"
+	ImagePop00    ImageName = "pop00"
+	ImagePop01    ImageName = "pop01"
+	ImagePop02    ImageName = "pop02"
+	ImagePop03    ImageName = "pop03"
+	ImagePop04    ImageName = "pop04"
+	ImagePop05    ImageName = "pop05"
+	ImagePop06    ImageName = "pop06"
+	ImagePop10    ImageName = "pop10"
+	ImagePop11    ImageName = "pop11"
+	ImagePop12    ImageName = "pop12"
+	ImagePop13    ImageName = "pop13"
+	ImagePop14    ImageName = "pop14"
+	ImagePop15    ImageName = "pop15"
+	ImagePop16    ImageName = "pop16"
+	ImageRecoil0  ImageName = "recoil0"
+	ImageRobot000 ImageName = "robot000"
+	ImageRobot001 ImageName = "robot001"
+	ImageRobot002 ImageName = "robot002"
+	ImageRobot003 ImageName = "robot003"
+	ImageRobot004 ImageName = "robot004"
+	ImageRobot005 ImageName = "robot005"
+	ImageRobot006 ImageName = "robot006"
+	ImageRobot007 ImageName = "robot007"
+	ImageRobot010 ImageName = "robot010"
+	ImageRobot011 ImageName = "robot011"
+	ImageRobot012 ImageName = "robot012"
+	ImageRobot013 ImageName = "robot013"
+	ImageRobot014 ImageName = "robot014"
+	ImageRobot015 ImageName = "robot015"
+	ImageRobot016 ImageName = "robot016"
+	ImageRobot017 ImageName = "robot017"
+	ImageRobot100 ImageName = "robot100"
+	ImageRobot101 ImageName = "robot101"
+	ImageRobot102 ImageName = "robot102"
+	ImageRobot103 ImageName = "robot103"
+	ImageRobot104 ImageName = "robot104"
+	ImageRobot105 ImageName = "robot105"
+	ImageRobot106 ImageName = "robot106"
+	ImageRobot107 ImageName = "robot107"
+	ImageRobot110 ImageName = "robot110"
+	ImageRobot111 ImageName = "robot111"
+	ImageRobot112 ImageName = "robot112"
+	ImageRobot113 ImageName = "robot113"
+	ImageRobot114 ImageName = "robot114"
+	ImageRobot115 ImageName = "robot115"
+	ImageRobot116 ImageName = "robot116"
+	ImageRobot117 ImageName = "robot117"
+	ImageRun00    ImageName = "run00"
+	ImageRun01    ImageName = "run01"
+	ImageRun02    ImageName = "run02"
+	ImageRun03    ImageName = "run03"
+	ImageStand0   ImageName = "stand0"
+	ImageStand1   ImageName = "stand1"
+	ImageStill    ImageName = "still"
+	ImageTitle    ImageName = "title"
+	ImageTrap00   ImageName = "trap00"
+	ImageTrap01   ImageName = "trap01"
+	ImageTrap02   ImageName = "trap02"
+	ImageTrap03   ImageName = "trap03"
+	ImageTrap04   ImageName = "trap04"
+	ImageTrap05   ImageName = "trap05"
+	ImageTrap06   ImageName = "trap06"
+	ImageTrap07   ImageName = "trap07"
+	ImageTrap10   ImageName = "trap10"
+	ImageTrap11   ImageName = "trap11"
+	ImageTrap12   ImageName = "trap12"
+	ImageTrap13   ImageName = "trap13"
+	ImageTrap14   ImageName = "trap14"
+	ImageTrap15   ImageName = "trap15"
+	ImageTrap16   ImageName = "trap16"
+	ImageTrap17   ImageName = "trap17"
+)
+
+// Groups of files in the images folder, indexed by the digits at the end of their names
+var (
+	ImagesBg    = [4]ImageName{ImageBg0, ImageBg1, ImageBg2, ImageBg3}
+	ImagesBlock = [4]ImageName{ImageBlock0, ImageBlock1, ImageBlock2, ImageBlock3}
+	ImagesBolt  = [2][2]ImageName{
+		{ImageBolt00, ImageBolt01},
+		{ImageBolt10, ImageBolt11},
+	}
+	ImagesFall  = [2]ImageName{ImageFall0, ImageFall1}
+	ImagesFruit = [5][3]ImageName{
+		{ImageFruit00, ImageFruit01, ImageFruit02},
+		{ImageFruit10, ImageFruit11, ImageFruit12},
+		{ImageFruit20, ImageFruit21, ImageFruit22},
+		{ImageFruit30, ImageFruit31, ImageFruit32},
+		{ImageFruit40, ImageFruit41, ImageFruit42},
+	}
+	ImagesOrb = [7]ImageName{ImageOrb0, ImageOrb1, ImageOrb2, ImageOrb3, ImageOrb4, ImageOrb5, ImageOrb6}
+	ImagesPop = [2][7]ImageName{
+		{ImagePop00, ImagePop01, ImagePop02, ImagePop03, ImagePop04, ImagePop05, ImagePop06},
+		{ImagePop10, ImagePop11, ImagePop12, ImagePop13, ImagePop14, ImagePop15, ImagePop16},
+	}
+	ImagesRobot = [2][2][8]ImageName{
+		{
+			{ImageRobot000, ImageRobot001, ImageRobot002, ImageRobot003, ImageRobot004, ImageRobot005, ImageRobot006, ImageRobot007},
+			{ImageRobot010, ImageRobot011, ImageRobot012, ImageRobot013, ImageRobot014, ImageRobot015, ImageRobot016, ImageRobot017},
+		},
+		{
+			{ImageRobot100, ImageRobot101, ImageRobot102, ImageRobot103, ImageRobot104, ImageRobot105, ImageRobot106, ImageRobot107},
+			{ImageRobot110, ImageRobot111, ImageRobot112, ImageRobot113, ImageRobot114, ImageRobot115, ImageRobot116, ImageRobot117},
+		},
+	}
+	ImagesRun = [1][4]ImageName{
+		{ImageRun00, ImageRun01, ImageRun02, ImageRun03},
+	}
+	ImagesStand = [2]ImageName{ImageStand0, ImageStand1}
+	ImagesTrap  = [2][8]ImageName{
+		{ImageTrap00, ImageTrap01, ImageTrap02, ImageTrap03, ImageTrap04, ImageTrap05, ImageTrap06, ImageTrap07},
+		{ImageTrap10, ImageTrap11, ImageTrap12, ImageTrap13, ImageTrap14, ImageTrap15, ImageTrap16, ImageTrap17},
+	}
+)
+
+// allImages lists all the files in the images folder
+var allImages = []ImageName{
+	ImageBg0,
+	ImageBg1,
+	ImageBg2,
+	ImageBg3,
+	ImageBlock0,
+	ImageBlock1,
+	ImageBlock2,
+	ImageBlock3,
+	ImageBlow0,
+	ImageBolt00,
+	ImageBolt01,
+	ImageBolt10,
+	ImageBolt11,
+	ImageCursor,
+	ImageFall0,
+	ImageFall1,
+	ImageFruit00,
+	ImageFruit01,
+	ImageFruit02,
+	ImageFruit10,
+	ImageFruit11,
+	ImageFruit12,
+	ImageFruit20,
+	ImageFruit21,
+	ImageFruit22,
+	ImageFruit30,
+	ImageFruit31,
+	ImageFruit32,
+	ImageFruit40,
+	ImageFruit41,
+	ImageFruit42,
+	ImageHealth,
+	ImageJump0,
+	ImageLife,
+	ImageOrb0,
+	ImageOrb1,
+	ImageOrb2,
+	ImageOrb3,
+	ImageOrb4,
+	ImageOrb5,
+	ImageOrb6,
+	ImagePlus,
+	ImagePop00,
+	ImagePop01,
+	ImagePop02,
+	ImagePop03,
+	ImagePop04,
+	ImagePop05,
+	ImagePop06,
+	ImagePop10,
+	ImagePop11,
+	ImagePop12,
+	ImagePop13,
+	ImagePop14,
+	ImagePop15,
+	ImagePop16,
+	ImageRecoil0,
+	ImageRobot000,
+	ImageRobot001,
+	ImageRobot002,
+	ImageRobot003,
+	ImageRobot004,
+	ImageRobot005,
+	ImageRobot006,
+	ImageRobot007,
+	ImageRobot010,
+	ImageRobot011,
+	ImageRobot012,
+	ImageRobot013,
+	ImageRobot014,
+	ImageRobot015,
+	ImageRobot016,
+	ImageRobot017,
+	ImageRobot100,
+	ImageRobot101,
+	ImageRobot102,
+	ImageRobot103,
+	ImageRobot104,
+	ImageRobot105,
+	ImageRobot106,
+	ImageRobot107,
+	ImageRobot110,
+	ImageRobot111,
+	ImageRobot112,
+	ImageRobot113,
+	ImageRobot114,
+	ImageRobot115,
+	ImageRobot116,
+	ImageRobot117,
+	ImageRun00,
+	ImageRun01,
+	ImageRun02,
+	ImageRun03,
+	ImageStand0,
+	ImageStand1,
+	ImageStill,
+	ImageTitle,
+	ImageTrap00,
+	ImageTrap01,
+	ImageTrap02,
+	ImageTrap03,
+	ImageTrap04,
+	ImageTrap05,
+	ImageTrap06,
+	ImageTrap07,
+	ImageTrap10,
+	ImageTrap11,
+	ImageTrap12,
+	ImageTrap13,
+	ImageTrap14,
+	ImageTrap15,
+	ImageTrap16,
+	ImageTrap17,
+}
+
+// Files in the sounds folder
+const (
+	SoundAppear0 SoundName = "appear0"
+	SoundBlow0   SoundName = "blow0"
+	SoundBlow1   SoundName = "blow1"
+	SoundBlow2   SoundName = "blow2"
+	SoundBlow3   SoundName = "blow3"
+	SoundBonus0  SoundName = "bonus0"
+	SoundDie0    SoundName = "die0"
+	SoundJump0   SoundName = "jump0"
+	SoundLand0   SoundName = "land0"
+	SoundLand1   SoundName = "land1"
+	SoundLand2   SoundName = "land2"
+	SoundLand3   SoundName = "land3"
+	SoundLaser0  SoundName = "laser0"
+	SoundLaser1  SoundName = "laser1"
+	SoundLaser2  SoundName = "laser2"
+	SoundLaser3  SoundName = "laser3"
+	SoundLevel0  SoundName = "level0"
+	SoundLife0   SoundName = "life0"
+	SoundOuch0   SoundName = "ouch0"
+	SoundOuch1   SoundName = "ouch1"
+	SoundOuch2   SoundName = "ouch2"
+	SoundOuch3   SoundName = "ouch3"
+	SoundOver0   SoundName = "over0"
+	SoundPop0    SoundName = "pop0"
+	SoundPop1    SoundName = "pop1"
+	SoundPop2    SoundName = "pop2"
+	SoundPop3    SoundName = "pop3"
+	SoundScore0  SoundName = "score0"
+	SoundSpark0  SoundName = "spark0"
+	SoundTrap0   SoundName = "trap0"
+	SoundTrap1   SoundName = "trap1"
+	SoundTrap2   SoundName = "trap2"
+	SoundTrap3   SoundName = "trap3"
+	SoundVanish0 SoundName = "vanish0"
+)
+
+// Groups of files in the sounds folder, indexed by the digits at the end of their names
+var (
+	SoundsBlow  = [4]SoundName{SoundBlow0, SoundBlow1, SoundBlow2, SoundBlow3}
+	SoundsLand  = [4]SoundName{SoundLand0, SoundLand1, SoundLand2, SoundLand3}
+	SoundsLaser = [4]SoundName{SoundLaser0, SoundLaser1, SoundLaser2, SoundLaser3}
+	SoundsOuch  = [4]SoundName{SoundOuch0, SoundOuch1, SoundOuch2, SoundOuch3}
+	SoundsPop   = [4]SoundName{SoundPop0, SoundPop1, SoundPop2, SoundPop3}
+	SoundsTrap  = [4]SoundName{SoundTrap0, SoundTrap1, SoundTrap2, SoundTrap3}
+)
+
+// allSounds lists all the files in the sounds folder
+var allSounds = []SoundName{
+	SoundAppear0,
+	SoundBlow0,
+	SoundBlow1,
+	SoundBlow2,
+	SoundBlow3,
+	SoundBonus0,
+	SoundDie0,
+	SoundJump0,
+	SoundLand0,
+	SoundLand1,
+	SoundLand2,
+	SoundLand3,
+	SoundLaser0,
+	SoundLaser1,
+	SoundLaser2,
+	SoundLaser3,
+	SoundLevel0,
+	SoundLife0,
+	SoundOuch0,
+	SoundOuch1,
+	SoundOuch2,
+	SoundOuch3,
+	SoundOver0,
+	SoundPop0,
+	SoundPop1,
+	SoundPop2,
+	SoundPop3,
+	SoundScore0,
+	SoundSpark0,
+	SoundTrap0,
+	SoundTrap1,
+	SoundTrap2,
+	SoundTrap3,
+	SoundVanish0,
+}
+
+// Files in the music folder
+const (
+	MusicTheme = "theme"
+)
@@ -2,7 +2,9 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"io/fs"
 	"io/ioutil"
 	"log"
 
@@ -10,14 +12,16 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
 )
 
-// AudioPlayer represents the current audio state.
+// AudioPlayer plays a music track in a loop.
 type AudioPlayer struct {
 	audioContext *audio.Context
 	audioPlayer  *audio.Player
-	volume128    int
+	track        string
 }
 
-func NewAudioPlayer(audioContext *audio.Context) (*AudioPlayer, error) {
+// NewAudioPlayer loads the music track from the music folder.
+// The default track is loaded instead when the track is not available.
+func NewAudioPlayer(audioContext *audio.Context, track string) (*AudioPlayer, error) {
 	type audioStream interface {
 		io.ReadSeeker
 		Length() int64
@@ -25,7 +29,12 @@ func NewAudioPlayer(audioContext *audio.Context) (*AudioPlayer, error) {
 
 	var s audioStream
 	var err error
-	file, err := embededFiles.Open("music/theme.ogg")
+	file, err := assetFiles.Open(musicFile(track))
+	if errors.Is(err, fs.ErrNotExist) && track != musicDefault {
+		log.Printf("music track %q not found: using %q instead", track, musicDefault)
+		track = musicDefault
+		file, err = assetFiles.Open(musicFile(track))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -46,26 +55,41 @@ func NewAudioPlayer(audioContext *audio.Context) (*AudioPlayer, error) {
 	player := &AudioPlayer{
 		audioContext: audioContext,
 		audioPlayer:  p,
-		volume128:    12,
+		track:        track,
 	}
-	player.audioPlayer.SetVolume(float64(player.volume128) / 128)
-	player.audioPlayer.Play()
-
 	return player, nil
 }
 
+// Track returns the name of the track loaded
+func (p *AudioPlayer) Track() string {
+	return p.track
+}
+
+// SetVolume changes the music volume (between 0 and 1)
+func (p *AudioPlayer) SetVolume(volume float64) {
+	p.audioPlayer.SetVolume(volume)
+}
+
+// Pause the music
+func (p *AudioPlayer) Pause() {
+	p.audioPlayer.Pause()
+}
+
+// Play (or resume) the music
+func (p *AudioPlayer) Play() {
+	p.audioPlayer.Play()
+}
+
+// Rewind the music to the start
+func (p *AudioPlayer) Rewind() error {
+	return p.audioPlayer.Rewind()
+}
+
 // Close the audio player
 func (p *AudioPlayer) Close() error {
 	return p.audioPlayer.Close()
 }
 
-// PlaySE plays a sound effect.
-func PlaySE(audioContext *audio.Context, bs []byte) {
-	if bs == nil || len(bs) == 0 {
-		log.Printf("cannot play empty sound")
-		return
-	}
-	sePlayer := audio.NewPlayerFromBytes(audioContext, bs)
-	// sePlayer is never GCed as long as it plays.
-	sePlayer.Play()
+func musicFile(track string) string {
+	return "music/" + track + ".ogg"
 }
@@ -7,29 +7,27 @@ import (
 
 type Bolt struct {
 	*Collide
-	leftImages  []*ebiten.Image
-	rightImages []*ebiten.Image
-	directionX  float64
-	active      bool
+	animations [2]*lib.Animation // left and right
+	directionX float64
+	active     bool
 }
 
 func NewBolt(level *Level) *Bolt {
 	sprite := lib.NewSprite(lib.XCentre, lib.YCentre)
 	return &Bolt{
-		Collide:     NewCollide(level, sprite),
-		leftImages:  []*ebiten.Image{images["bolt00"], images["bolt01"]},
-		rightImages: []*ebiten.Image{images["bolt00"], images["bolt01"]},
+		Collide:    NewCollide(level, sprite),
+		animations: [2]*lib.Animation{Animation(AnimationsBolt[0]), Animation(AnimationsBolt[1])},
 	}
 }
 
 func (b *Bolt) Fire(directionX, x, y float64) *Bolt {
 	b.directionX = directionX
 	b.active = true
-	b.MoveTo(x, y)
+	b.Teleport(x, y)
 	if b.directionX == -1 {
-		b.Animate(b.leftImages, nil, 4, true)
+		b.Play(b.animations[0])
 	} else if b.directionX == 1 {
-		b.Animate(b.rightImages, nil, 4, true)
+		b.Play(b.animations[1])
 	}
 	return b
 }
@@ -43,6 +41,9 @@ func (b *Bolt) Update(game *Game) {
 	ok := b.CollideMove(b.directionX, 0, BoltSpeed)
 	if !ok {
 		b.active = false
+		effect, x := b.impact()
+		game.particles.Burst(effect, x, b.Y(lib.YCentre))
+		game.SoundEffect(SoundSpark0, x, b.Y(lib.YCentre))
 		return
 	}
 	// collision with an orb
@@ -68,6 +69,14 @@ func (b *Bolt) Draw(screen *ebiten.Image) {
 	b.Sprite.Draw(screen)
 }
 
+// impact returns the sparks of the bolt hitting a wall, and the position of its front edge
+func (b *Bolt) impact() (*lib.ParticleEffect, float64) {
+	if b.directionX == -1 {
+		return EffectsBoltImpact[0], b.X(lib.XLeft)
+	}
+	return EffectsBoltImpact[1], b.X(lib.XRight)
+}
+
 func (b *Bolt) IsActive() bool {
 	return b.active
 }
@@ -0,0 +1,248 @@
+// Command assetgen scans the images, sounds and music folders and generates typed constants for each asset,
+// so a typo in an asset name is a compilation error instead of a missing image at runtime.
+//
+// Assets named with a common prefix followed by digits are also grouped together:
+// robot000 to robot117 become ImagesRobot[type][direction][frame].
+//
+//	go generate
+//	go run ./cmd/assetgen -o assets_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// folder describes the assets found in a folder
+type folder struct {
+	dir        string
+	extensions []string
+	typeName   string // type of the constants, untyped when empty
+	prefix     string // prefix of the constant names
+	group      string // prefix of the group names, no group when empty
+	list       string // name of the variable listing all the constants, no list when empty
+}
+
+var folders = []folder{
+	{dir: "images", extensions: []string{".png"}, typeName: "ImageName", prefix: "Image", group: "Images", list: "allImages"},
+	{dir: "sounds", extensions: []string{".ogg", ".json"}, typeName: "SoundName", prefix: "Sound", group: "Sounds", list: "allSounds"},
+	{dir: "music", extensions: []string{".ogg"}, prefix: "Music"},
+}
+
+func main() {
+	var root, output string
+	flag.StringVar(&root, "root", ".", "folder containing the images, sounds and music folders")
+	flag.StringVar(&output, "o", "assets_gen.go", "Go file to generate")
+	flag.Parse()
+
+	source, err := generate(root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	err = os.WriteFile(output, source, 0o644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// generate returns the formatted Go source of the asset constants
+func generate(root string) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("// Code generated by go run ./cmd/assetgen; DO NOT EDIT.\n\npackage main\n")
+	for _, f := range folders {
+		names, err := assetNames(filepath.Join(root, f.dir), f.extensions)
+		if err != nil {
+			return nil, err
+		}
+		writeFolder(buffer, f, names)
+	}
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code: %w", err)
+	}
+	return source, nil
+}
+
+// assetNames returns the sorted file names without extension (a name is only listed once)
+func assetNames(dir string, extensions []string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := path.Ext(entry.Name())
+		for _, extension := range extensions {
+			if ext == extension {
+				found[strings.TrimSuffix(entry.Name(), ext)] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func writeFolder(buffer *bytes.Buffer, f folder, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	fmt.Fprintf(buffer, "\n// Files in the %s folder\nconst (\n", f.dir)
+	for _, name := range names {
+		fmt.Fprintf(buffer, "\t%s %s = %q\n", identifier(f.prefix, name), f.typeName, name)
+	}
+	buffer.WriteString(")\n")
+
+	if f.group != "" {
+		groups := groupNames(names)
+		if len(groups) > 0 {
+			fmt.Fprintf(buffer, "\n// Groups of files in the %s folder, indexed by the digits at the end of their names\nvar (\n", f.dir)
+			for _, g := range groups {
+				fmt.Fprintf(buffer, "\t%s = ", identifier(f.group, g.prefix))
+				g.write(buffer, f.prefix, f.typeName)
+				buffer.WriteString("\n")
+			}
+			buffer.WriteString(")\n")
+		}
+	}
+
+	if f.list != "" {
+		fmt.Fprintf(buffer, "\n// %s lists all the files in the %s folder\nvar %s = []%s{\n", f.list, f.dir, f.list, f.typeName)
+		for _, name := range names {
+			fmt.Fprintf(buffer, "\t%s,\n", identifier(f.prefix, name))
+		}
+		buffer.WriteString("}\n")
+	}
+}
+
+// group is a list of assets with the same prefix and the same number of digits at the end of their names
+type group struct {
+	prefix string
+	names  []string // sorted
+	dims   []int    // size of each dimension when the group is a complete grid, nil otherwise
+}
+
+// groupNames returns the groups of at least two assets
+func groupNames(names []string) []*group {
+	byKey := make(map[string]*group)
+	keys := make([]string, 0)
+	for _, name := range names {
+		prefix := strings.TrimRightFunc(name, unicode.IsDigit)
+		digits := len(name) - len(prefix)
+		if prefix == "" || digits == 0 {
+			continue
+		}
+		key := prefix
+		if g, found := byKey[key]; found && len(g.names[0])-len(prefix) != digits {
+			// same prefix with a different number of digits: cannot be a single group
+			key = fmt.Sprintf("%s%d", prefix, digits)
+		}
+		g, found := byKey[key]
+		if !found {
+			g = &group{prefix: prefix}
+			byKey[key] = g
+			keys = append(keys, key)
+		}
+		g.names = append(g.names, name)
+	}
+	groups := make([]*group, 0, len(keys))
+	for _, key := range keys {
+		g := byKey[key]
+		if len(g.names) < 2 {
+			continue
+		}
+		g.dims = grid(g.prefix, g.names)
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// grid returns the dimensions of the group when each digit is an index from 0 with no gap, nil otherwise
+func grid(prefix string, names []string) []int {
+	digits := len(names[0]) - len(prefix)
+	dims := make([]int, digits)
+	for _, name := range names {
+		for i, digit := range name[len(prefix):] {
+			if index := int(digit-'0') + 1; index > dims[i] {
+				dims[i] = index
+			}
+		}
+	}
+	total := 1
+	for _, dim := range dims {
+		total *= dim
+	}
+	if total != len(names) {
+		return nil
+	}
+	return dims
+}
+
+// write the group as nested arrays (complete grid) or as a slice
+func (g *group) write(buffer *bytes.Buffer, prefix, typeName string) {
+	if g.dims == nil {
+		fmt.Fprintf(buffer, "[]%s{", typeName)
+		for i, name := range g.names {
+			if i > 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(identifier(prefix, name))
+		}
+		buffer.WriteString("}")
+		return
+	}
+	for _, dim := range g.dims {
+		fmt.Fprintf(buffer, "[%d]", dim)
+	}
+	buffer.WriteString(typeName)
+	g.writeDimension(buffer, prefix, g.prefix, 0)
+}
+
+// writeDimension writes the last dimension on a single line, and one line per item for the other dimensions
+func (g *group) writeDimension(buffer *bytes.Buffer, prefix, name string, dimension int) {
+	last := dimension == len(g.dims)-1
+	buffer.WriteString("{")
+	if !last {
+		buffer.WriteString("\n")
+	}
+	for i := 0; i < g.dims[dimension]; i++ {
+		indexed := fmt.Sprintf("%s%d", name, i)
+		if last {
+			if i > 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(identifier(prefix, indexed))
+			continue
+		}
+		g.writeDimension(buffer, prefix, indexed, dimension+1)
+		buffer.WriteString(",\n")
+	}
+	buffer.WriteString("}")
+}
+
+// identifier returns a Go identifier from a file name: "robot-blue_01" becomes "RobotBlue01"
+func identifier(prefix, name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return prefix + strings.Join(words, "")
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedFileUpToDate(t *testing.T) {
+	expected, err := generate("../..")
+	require.NoError(t, err)
+	actual, err := os.ReadFile("../../assets_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual), "assets_gen.go is out of date: run go generate")
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "ImageRobot104", identifier("Image", "robot104"))
+	assert.Equal(t, "SoundRobotBlue01", identifier("Sound", "robot-blue_01"))
+	assert.Equal(t, "MusicHighscore", identifier("Music", "highscore"))
+}
+
+func TestGroupNames(t *testing.T) {
+	groups := groupNames([]string{"a0", "a1", "b00", "b01", "b10", "b11", "c0", "c2", "d0", "title", "e1", "e10"})
+	require.Len(t, groups, 3)
+
+	assert.Equal(t, "a", groups[0].prefix)
+	assert.Equal(t, []int{2}, groups[0].dims)
+
+	assert.Equal(t, "b", groups[1].prefix)
+	assert.Equal(t, []int{2, 2}, groups[1].dims)
+
+	// gap in the numbers: not a grid
+	assert.Equal(t, "c", groups[2].prefix)
+	assert.Equal(t, []string{"c0", "c2"}, groups[2].names)
+	assert.Nil(t, groups[2].dims)
+}
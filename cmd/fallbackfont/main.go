@@ -0,0 +1,184 @@
+// Command fallbackfont cuts the glyphs needed by the message files out of a large bitmap font,
+// into a small glyph sheet used for the characters missing from the TrueType fonts (like Japanese).
+// Only the glyph sheet is embedded in the game, not the whole bitmap font.
+//
+//	go generate
+//	go run ./cmd/fallbackfont -o fonts/fallback
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/bitmapfont/v3"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// sheetWidth is the width of the glyph sheet, in pixels
+const sheetWidth = 256
+
+func main() {
+	var root, output string
+	flag.StringVar(&root, "root", ".", "folder containing the lang folder and the TrueType font")
+	flag.StringVar(&output, "o", "fonts/fallback", "glyph sheet (.png) and metrics file (.txt) to generate, without extension")
+	flag.Parse()
+
+	err := generate(root, output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func generate(root, output string) error {
+	covered, err := loadCoverage(filepath.Join(root, "wasm", "destructobeambb_reg.ttf"))
+	if err != nil {
+		return err
+	}
+	chars, err := neededChars(filepath.Join(root, "lang"), covered)
+	if err != nil {
+		return err
+	}
+	face := bitmapfont.FaceEA
+	for _, char := range chars {
+		if _, ok := face.GlyphAdvance(char); !ok {
+			fmt.Fprintf(os.Stderr, "character %q (%U) is missing from the bitmap font\n", char, char)
+		}
+	}
+	sheet, metrics := render(face, chars)
+
+	file, err := os.Create(output + ".png")
+	if err != nil {
+		return err
+	}
+	err = png.Encode(file, sheet)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	err = os.WriteFile(output+".txt", []byte(metrics), 0o644)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d glyphs\n", output, len(chars))
+	return nil
+}
+
+// loadCoverage returns a function telling if a character is in the TrueType fonts of the game
+func loadCoverage(trueTypeFont string) (func(rune) bool, error) {
+	data, err := os.ReadFile(trueTypeFont)
+	if err != nil {
+		return nil, err
+	}
+	fonts := make([]*sfnt.Font, 0, 2)
+	for _, data := range [][]byte{data, gobold.TTF} {
+		parsed, err := sfnt.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", trueTypeFont, err)
+		}
+		fonts = append(fonts, parsed)
+	}
+	buffer := &sfnt.Buffer{}
+	return func(char rune) bool {
+		for _, f := range fonts {
+			index, err := f.GlyphIndex(buffer, char)
+			if err == nil && index != 0 {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// neededChars returns the characters of all the messages which cannot be drawn with the TrueType fonts:
+// these messages are drawn entirely with the glyph sheet. The printable ASCII characters are always included,
+// for the numbers and names inserted in the messages.
+func neededChars(langDir string, covered func(rune) bool) ([]rune, error) {
+	filenames, err := filepath.Glob(filepath.Join(langDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[rune]bool, 256)
+	for char := rune(' '); char <= '~'; char++ {
+		found[char] = true
+	}
+	for _, filename := range filenames {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		messages := make(map[string]string)
+		err = json.Unmarshal(data, &messages)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		for _, message := range messages {
+			if strings.IndexFunc(message, func(char rune) bool { return char != '\n' && !covered(char) }) == -1 {
+				continue
+			}
+			for _, char := range message {
+				if char != '\n' {
+					found[char] = true
+				}
+			}
+		}
+	}
+	chars := make([]rune, 0, len(found))
+	for char := range found {
+		chars = append(chars, char)
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	return chars, nil
+}
+
+// render draws the glyphs in rows, each in a cell as wide as its advance and as high as a line
+func render(face font.Face, chars []rune) (*image.NRGBA, string) {
+	lineMetrics := face.Metrics()
+	height := lineMetrics.Height.Ceil()
+	ascent := lineMetrics.Ascent.Ceil()
+
+	metrics := &strings.Builder{}
+	metrics.WriteString("# Fallback glyphs generated by cmd/fallbackfont: do not edit\n")
+	metrics.WriteString("# glyph <code point> <x> <y> <width> <height> <advance>\n")
+	fmt.Fprintf(metrics, "height %d\nascent %d\n", height, ascent)
+
+	x, y := 0, 0
+	positions := make([]image.Point, len(chars))
+	for i, char := range chars {
+		advance, _ := face.GlyphAdvance(char)
+		width := advance.Ceil()
+		if x+width > sheetWidth {
+			x = 0
+			y += height
+		}
+		positions[i] = image.Pt(x, y)
+		fmt.Fprintf(metrics, "glyph %d %d %d %d %d %d\n", char, x, y, width, height, width)
+		x += width
+	}
+
+	sheet := image.NewNRGBA(image.Rect(0, 0, sheetWidth, y+height))
+	drawer := &font.Drawer{
+		Dst:  sheet,
+		Src:  image.NewUniform(color.White),
+		Face: face,
+	}
+	for i, char := range chars {
+		drawer.Dot = fixed.P(positions[i].X, positions[i].Y+ascent)
+		drawer.DrawString(string(char))
+	}
+	return sheet, metrics.String()
+}
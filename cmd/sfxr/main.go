@@ -0,0 +1,94 @@
+// Command sfxr renders a sound effect parameter file (or a preset) to a WAV file for auditioning.
+//
+//	go run ./cmd/sfxr -o spark.wav sounds/spark0.json
+//	go run ./cmd/sfxr -preset pop -o pop.wav
+//	go run ./cmd/sfxr -preset pop -dump > sounds/pop4.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/creativeprojects/cavern/synth"
+)
+
+func main() {
+	var preset, output string
+	var dump bool
+	flag.StringVar(&preset, "preset", "", "use a preset instead of a parameter file ("+strings.Join(presetNames(), ", ")+")")
+	flag.StringVar(&output, "o", "sfx.wav", "WAV file to generate")
+	flag.BoolVar(&dump, "dump", false, "print the parameters as JSON instead of generating a WAV file")
+	flag.Parse()
+
+	params, err := loadParams(preset, flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if dump {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(params)
+	} else {
+		err = render(params, output)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func loadParams(preset, filename string) (synth.Params, error) {
+	if preset != "" {
+		params, found := synth.Presets[preset]
+		if !found {
+			return params, fmt.Errorf("unknown preset %q", preset)
+		}
+		return params, nil
+	}
+	if filename == "" {
+		return synth.Params{}, fmt.Errorf("missing parameter file")
+	}
+	params := synth.Params{}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return params, err
+	}
+	err = json.Unmarshal(data, &params)
+	if err != nil {
+		return params, fmt.Errorf("%s: %w", filename, err)
+	}
+	return params, nil
+}
+
+func render(params synth.Params, output string) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	err = synth.WriteWAV(file, synth.Render(params, synth.SampleRate), synth.SampleRate)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	fmt.Printf("%s: %.2f seconds\n", output, params.Duration())
+	return nil
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(synth.Presets))
+	for name := range synth.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
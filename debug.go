@@ -5,24 +5,30 @@ package main
 import (
 	"fmt"
 	"image/color"
+	"math"
 
+	"github.com/creativeprojects/cavern/lib"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 var Debug = true
 
 func (g *Game) displayDebug(screen *ebiten.Image) {
-	template := " TPS: %0.2f \n Level %d - Colour %d \n Fruits %d - Pops %d - Orbs %d - Robots %d - Bolts %d \n%s"
+	template := " TPS: %0.2f \n Level %d - Theme %s \n Fruits %d - Pops %d - Orbs %d - Robots %d - Bolts %d - Particles %d \n Voices %d - Muted %v \n%s"
 	msg := fmt.Sprintf(template,
 		ebiten.CurrentTPS(),
 		g.level.id,
-		g.level.colour,
+		g.level.themeName,
 		len(g.fruits),
 		len(g.pops),
 		len(g.orbs),
 		len(g.robots),
 		len(g.bolts),
+		g.particles.Count(),
+		g.mixer.Voices(),
+		g.mixer.IsMuted(),
 		g.player,
 	)
 	fruitTemplate := " Fruit %d: ttl: %d coordinates: %s\n"
@@ -35,6 +41,80 @@ func (g *Game) displayDebug(screen *ebiten.Image) {
 	ebitenutil.DrawLine(screen, 730, 75, 730, 400, color.White)
 	ebitenutil.DrawLine(screen, 70, 75, 730, 75, color.White)
 	ebitenutil.DrawLine(screen, 70, 400, 730, 400, color.White)
+
+	g.displayHitAreas(screen)
+}
+
+// displayHitAreas draws the outline of the areas used to detect collisions
+func (g *Game) displayHitAreas(screen *ebiten.Image) {
+	if g.player != nil {
+		drawHitArea(screen, g.player.sprite, color.RGBA{0x00, 0xff, 0x00, 0xff})
+	}
+	for _, robot := range g.robots {
+		if robot.IsAlive() {
+			drawHitArea(screen, robot.Sprite, color.RGBA{0xff, 0x00, 0x00, 0xff})
+		}
+	}
+	for _, orb := range g.orbs {
+		if orb.IsActive() {
+			drawHitArea(screen, orb.Sprite, color.RGBA{0x00, 0xff, 0xff, 0xff})
+		}
+	}
+	for _, bolt := range g.bolts {
+		if bolt.IsActive() {
+			drawHitArea(screen, bolt.Sprite, color.RGBA{0xff, 0xff, 0x00, 0xff})
+		}
+	}
+	for _, fruit := range g.fruits {
+		drawHitArea(screen, fruit.Sprite, color.RGBA{0xff, 0x00, 0xff, 0xff})
+	}
+}
+
+func drawHitArea(screen *ebiten.Image, sprite *lib.Sprite, clr color.Color) {
+	switch area := sprite.HitArea().(type) {
+	case lib.Rect:
+		vector.StrokeRect(screen, float32(area.X), float32(area.Y), float32(area.Width), float32(area.Height), 1, clr, false)
+	case lib.Circle:
+		vector.StrokeCircle(screen, float32(area.X), float32(area.Y), float32(area.Radius), 1, clr, true)
+	}
+}
+
+// displayAtlas draws the atlas page, scaled down to fit the screen, with the outline of each image
+func (g *Game) displayAtlas(screen *ebiten.Image, page int) {
+	const top = 20.0
+	atlasPage := atlas.Pages()[page]
+	width, height := atlasPage.Bounds().Dx(), atlasPage.Bounds().Dy()
+	scale := math.Min(1, math.Min(WindowWidth/float64(width), (WindowHeight-top)/float64(height)))
+
+	vector.DrawFilledRect(screen, 0, 0, WindowWidth, WindowHeight, color.RGBA{0x40, 0x00, 0x40, 0xff}, false)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(0, top)
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(atlasPage, op)
+
+	count, area := 0, 0
+	for _, region := range atlas.Regions() {
+		if region.Page != page {
+			continue
+		}
+		count++
+		area += region.Bounds.Dx() * region.Bounds.Dy()
+		vector.StrokeRect(screen,
+			float32(float64(region.Bounds.Min.X)*scale),
+			float32(float64(region.Bounds.Min.Y)*scale+top),
+			float32(float64(region.Bounds.Dx())*scale),
+			float32(float64(region.Bounds.Dy())*scale),
+			1, color.RGBA{0x00, 0xff, 0x00, 0x80}, false)
+	}
+	ebitenutil.DebugPrint(screen, fmt.Sprintf(" Atlas page %d/%d: %dx%d - %d images - %.0f%% used - A: next page",
+		page+1,
+		len(atlas.Pages()),
+		width,
+		height,
+		count,
+		100*float64(area)/float64(width*height),
+	))
 }
 
 // String returns a debug string
@@ -1,5 +1,7 @@
 package main
 
+import "github.com/creativeprojects/cavern/synth"
+
 // Game defaults
 const (
 	WindowWidth                = 800.0
@@ -7,13 +9,14 @@ const (
 	NumRows                    = 18
 	NumColumns                 = 28
 	WindowTitle                = "Cavern"
-	SampleRate                 = 44100
+	SampleRate                 = synth.SampleRate
 	LeftGridOffset             = 50.0
 	GridBlockSize              = 25.0
 	MaxFallSpeed               = 10.0
 	NewFruitRate               = 100
 	NewEnemyRate               = 81
 	FruitTTL                   = 500 // this will keep 5 fruits maximum at all time
+	FruitFadeTime              = 100 // the fruit fades out during its last ticks
 	GameNormalSpeed            = 60
 	GameSlowSpeed              = 20
 	PlayerStartLives           = 2
@@ -25,4 +28,29 @@ const (
 	OrbMaxTimer                = 250
 	OrbFireTimer               = 20
 	BoltSpeed                  = 7.0
+	MaxVolume                  = 128
+	DefaultMusicVolume         = 12
+	DefaultEffectsVolume       = 128
+	VolumeStep                 = 4
+	MaxWindowScale             = 3
+	MaxVoices                  = 16
+	MaxVoicesPerSound          = 3
+	ArenaLeft                  = 70.0
+	ArenaRight                 = 730.0
+	MaxPan                     = 0.8
+	OffScreenVolume            = 0.6
+	MusicFadeTime              = 90
+	MusicDuckVolume            = 0.3
+	PopupTime                  = 60
+	PopupRise                  = 50.0
+	PopupRiseTime              = 30
+	BannerSlideTime            = 50
+	TitleFadeTime              = 60
+	ShakeTime                  = 30
+	ShakeAmplitude             = 8.0
+	MaxParticles               = 1000
+	LevelHurryTime             = 5400 // one minute and a half before the music goes faster
+	AtlasPageSize              = 2048
+	AtlasPadding               = 1
+	HotReloadRate              = 30 // check the files on disk twice a second (debug build only)
 )
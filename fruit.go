@@ -1,7 +1,9 @@
 package main
 
 import (
+	"math"
 	"math/rand"
+	"strconv"
 
 	"github.com/creativeprojects/cavern/lib"
 	"github.com/hajimehoshi/ebiten/v2"
@@ -26,26 +28,22 @@ const (
 type Fruit struct {
 	*Gravity
 	Type      FruitType
-	Animation [totalFruits][]*ebiten.Image
+	Animation [totalFruits]*lib.Animation
 	op        *ebiten.DrawImageOptions
 	TTL       int
 }
 
-var (
-	fruitAnimation = []int{0, 1, 2, 1}
-)
-
 // NewFruit creates a new random fruit. If extra is true there's a small chance to also create an extra life and extra health fruit.
 func NewFruit(level *Level, extra bool) *Fruit {
 	sprite := lib.NewSprite(lib.XCentre, lib.YBottom)
 	f := &Fruit{
 		Gravity: NewGravity(level, sprite),
-		Animation: [totalFruits][]*ebiten.Image{
-			{images["fruit00"], images["fruit01"], images["fruit02"]},
-			{images["fruit10"], images["fruit11"], images["fruit12"]},
-			{images["fruit20"], images["fruit21"], images["fruit22"]},
-			{images["fruit30"], images["fruit31"], images["fruit32"]},
-			{images["fruit40"], images["fruit41"], images["fruit42"]},
+		Animation: [totalFruits]*lib.Animation{
+			Animation(AnimationFruitApple),
+			Animation(AnimationFruitRaspberry),
+			Animation(AnimationFruitLemon),
+			Animation(AnimationFruitExtraHealth),
+			Animation(AnimationFruitExtraLife),
 		},
 		op: &ebiten.DrawImageOptions{},
 	}
@@ -84,8 +82,8 @@ func (f *Fruit) Generate(extra bool) *Fruit {
 	f.landed = false
 	f.TTL = FruitTTL
 	f.
-		MoveTo(float64(randomInt(70, 730)), float64(randomInt(75, 400))).
-		Animate(f.Animation[f.Type], fruitAnimation, 6, true)
+		Teleport(float64(randomInt(70, 730)), float64(randomInt(75, 400))).
+		Play(f.Animation[f.Type])
 	return f
 }
 
@@ -96,6 +94,7 @@ func (f *Fruit) Update(game *Game) {
 	}
 	f.Sprite.Update()
 	f.TTL--
+	f.SetAlpha(math.Min(1, float64(f.TTL)/FruitFadeTime))
 	if f.TTL == 0 {
 		// create pop animation
 		game.StartPop(PopFruit, f.X(lib.XCentre), f.Y(lib.YBottom))
@@ -103,15 +102,19 @@ func (f *Fruit) Update(game *Game) {
 	}
 	if game.player != nil && game.player.sprite.CollidePoint(f.X(lib.XCentre), f.Y(lib.YCentre)) {
 		f.TTL = 0
+		x, y := f.X(lib.XCentre), f.Y(lib.YCentre)
 		switch f.Type {
 		case ExtraHealth:
-			game.SoundEffect(sounds[soundBonus])
+			game.SoundEffect(SoundBonus0, x, y)
 		case ExtraLife:
-			game.SoundEffect(sounds[soundLife])
+			game.SoundEffect(SoundLife0, x, y)
 		default:
-			game.SoundEffect(sounds[soundScore])
+			game.SoundEffect(SoundScore0, x, y)
+		}
+		points := game.player.Eat(f.Type)
+		if points > 0 {
+			game.StartPopup(strconv.Itoa(points), x, f.Y(lib.YTop))
 		}
-		game.player.Eat(f.Type)
 	}
 	if f.landed {
 		return
@@ -1,66 +1,73 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"math"
 	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/creativeprojects/cavern/lib"
+	"github.com/creativeprojects/cavern/lib/tween"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
-var (
-	CharWidths = []int{27, 26, 25, 26, 25, 25, 26, 25, 12, 26, 26, 25, 33, 25, 26,
-		25, 27, 26, 26, 25, 26, 26, 38, 25, 25, 25}
-)
-
 // Game contains the current game state
 type Game struct {
-	audioContext *audio.Context
-	musicPlayer  *AudioPlayer
+	mixer        *Mixer
+	settings     *Settings
+	options      *Options
 	state        GameState
+	highScore    int
+	newHighScore bool // the game just finished with a new high score
 	slow         bool
 	debug        bool
+	atlasPage    int // atlas page displayed in debug mode (starting at 1), 0 when hidden
 	timer        float64
-	space        *lib.Sprite
 	level        *Level
 	player       *Player
 	fruits       []*Fruit
 	pops         []*Pop
+	popups       []*Popup
 	orbs         []*Orb
 	robots       []*Robot
 	bolts        []*Bolt
+	particles    *lib.ParticleSystem
+	titleAlpha   float64
+	titleFade    *tween.Tween
+	shake        float64 // amplitude of the camera shake, in pixels
+	shakeTween   *tween.Tween
+	layers       *lib.Layers
+	postProcess  *lib.PostProcess
+	frame        *ebiten.Image // the game is drawn here first when a shader is enabled
+	canvas       *ebiten.Image // the game screen, scaled to the window by the viewport
+	viewport     *lib.Viewport
+	tickTime     time.Time // start of the current tick
 }
 
 // NewGame creates a new game instance and prepares a demo AI game
-func NewGame(audioContext *audio.Context) (*Game, error) {
+func NewGame(audioContext *audio.Context, settings *Settings) (*Game, error) {
 
-	m, err := NewAudioPlayer(audioContext)
+	m, err := NewMixer(audioContext, musicTitle, Volume(settings.MusicVolume), Volume(settings.EffectsVolume))
 	if err != nil {
 		return nil, err
 	}
 
 	g := &Game{
-		audioContext: audioContext,
-		musicPlayer:  m,
-		state:        StateMenu,
-		slow:         false,
-		space: lib.NewSprite(lib.XCentre, lib.YCentre).MoveTo(400, 280+45).Animate([]*ebiten.Image{
-			images["space0"], images["space1"], images["space2"], images["space3"], images["space4"],
-			images["space5"], images["space6"], images["space7"], images["space8"], images["space9"],
-		}, nil, 4, true).SetSequenceFunc(func(counter int) int {
-			// Draw "Press SPACE" animation, which has 10 frames numbered 0 to 9
-			// The first part gives us a number between 0 and 159, based on the game timer
-			// Dividing by 4 means we go to a new animation frame every 4 frames
-			// We enclose this calculation in the min function, with the other argument being 9, which results in the
-			// animation staying on frame 9 for three quarters of the time. Adding 40 to the game timer is done to alter
-			// which stage the animation is at when the game first starts
-			return int(math.Min((math.Floor(math.Mod(float64(counter)+40, 160)) / 4), 9))
-		}),
+		mixer:    m,
+		settings: settings,
+		state:    StateMenu,
+		slow:     false,
 	}
+	g.viewport = lib.NewViewport(WindowWidth, WindowHeight).SetMode(settings.ScaleMode)
+	g.options = NewOptions(settings, g.ApplySettings, g.CursorPosition)
+	g.layers = g.newLayers()
+	g.particles = lib.NewParticleSystem(MaxParticles)
+	g.postProcess = newPostProcess()
+	g.applyPostProcess()
+	lib.SetInterpolation(settings.Interpolation)
 
 	return g.Initialize(), nil
 }
@@ -68,51 +75,135 @@ func NewGame(audioContext *audio.Context) (*Game, error) {
 // Initialize a new game
 func (g *Game) Initialize() *Game {
 	g.timer = -1
-	g.level = NewLevel()
+	g.level = NewLevel(g.settings.Difficulty)
 	g.level.Next()
 	g.fruits = make([]*Fruit, 0, 10)
 	g.pops = make([]*Pop, 0, 10)
+	g.popups = make([]*Popup, 0, 10)
 	g.orbs = make([]*Orb, MaxOrbs)
 	g.robots = make([]*Robot, 0, 10)
 	g.bolts = make([]*Bolt, 0, 10)
+	g.titleAlpha = 0
+	g.titleFade = tween.Float(&g.titleAlpha, 0, 1, TitleFadeTime, tween.QuadIn)
+	g.shake = 0
+	g.shakeTween = nil
+
+	g.particles.Clear()
+
+	world := g.layers.Layer(LayerWorld).Clear().SetEffect(nil)
+	world.Add(g.level, ZLevel)
+	world.Add(g.particles, ZParticles)
 
 	// create Orbs
 	for i := 0; i < MaxOrbs; i++ {
-		g.orbs[i] = NewOrb(g.level)
+		g.orbs[i] = NewOrb(g.level, g.particles)
+		world.Add(g.orbs[i], ZOrb)
 	}
 	return g
 }
 
-// Layout defines the size of the game in pixels
+// Layout uses all the pixels of the window: the game screen is scaled to fit by the viewport
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return WindowWidth, WindowHeight
+	scale := ebiten.Monitor().DeviceScaleFactor()
+	screenWidth = int(math.Ceil(float64(outsideWidth) * scale))
+	screenHeight = int(math.Ceil(float64(outsideHeight) * scale))
+	g.viewport.Resize(screenWidth, screenHeight)
+	return screenWidth, screenHeight
+}
+
+// CursorPosition returns the position of the mouse cursor on the game screen
+func (g *Game) CursorPosition() (float64, float64) {
+	x, y := ebiten.CursorPosition()
+	return g.viewport.ToGame(float64(x), float64(y))
+}
+
+// ToggleFullscreen switches between fullscreen and window, and saves the setting
+func (g *Game) ToggleFullscreen() {
+	g.settings.Fullscreen = !g.settings.Fullscreen
+	ebiten.SetFullscreen(g.settings.Fullscreen)
+	err := g.settings.Save()
+	if err != nil {
+		log.Printf("cannot save settings: %v", err)
+	}
 }
 
 // Start a new game
 func (g *Game) Start() *Game {
 	g.Initialize()
-	g.player = NewPlayer().Start(g.level)
+	g.player = NewPlayer(g.particles).Start(g.level, g.settings.Difficulty.StartLives())
+	g.layers.Add(LayerWorld, g.player, ZPlayer)
 	g.state = StatePlaying
 	return g
 }
 
+// OpenOptions shows the options screen
+func (g *Game) OpenOptions() {
+	g.options.Open(g.state)
+	g.state = StateOptions
+}
+
+// ApplySettings applies the changes made in the options screen
+func (g *Game) ApplySettings() {
+	g.mixer.SetMusicVolume(Volume(g.settings.MusicVolume))
+	g.mixer.SetEffectsVolume(Volume(g.settings.EffectsVolume))
+	messages.SetLanguage(g.settings.Language)
+	ebiten.SetFullscreen(g.settings.Fullscreen)
+	ebiten.SetWindowSize(WindowWidth*g.settings.WindowScale, WindowHeight*g.settings.WindowScale)
+	g.viewport.SetMode(g.settings.ScaleMode)
+	lib.SetInterpolation(g.settings.Interpolation)
+	g.applyPostProcess()
+}
+
 // NextLevel loads the next level
 func (g *Game) NextLevel() {
-	g.SoundEffect(sounds[soundLevel])
+	g.SoundEffect(SoundLevel0, WindowWidth/2, WindowHeight/2)
+	g.mixer.Duck(Sound(SoundLevel0))
 	g.level.Next()
 }
 
+// GameOver ends the current game
+func (g *Game) GameOver() {
+	g.state = StateGameOver
+	g.newHighScore = g.player != nil && g.player.score > g.highScore
+	if g.newHighScore {
+		g.highScore = g.player.score
+	}
+	g.SoundEffect(SoundOver0, WindowWidth/2, WindowHeight/2)
+	g.mixer.Duck(Sound(SoundOver0))
+}
+
 // Update game events
 func (g *Game) Update() error {
+	lib.NextTick()
+	g.tickTime = time.Now()
 	g.timer++
+	g.hotReload()
+	// the audio stays paused in the options screen opened from the pause screen
+	g.mixer.SetPaused(g.state == StatePaused || (g.state == StateOptions && g.options.From() == StatePaused))
+	err := g.mixer.PlayMusic(g.musicTrack())
+	if err != nil {
+		log.Printf("cannot play music: %v", err)
+	}
+	g.mixer.Update()
+
+	if g.state != StateOptions && inpututil.IsKeyJustPressed(g.settings.Key(ActionMute)) {
+		g.mixer.ToggleMute()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF11) {
+		g.ToggleFullscreen()
+	}
 
 	// Debug screen
 	if Debug && inpututil.IsKeyJustPressed(ebiten.KeyD) {
 		g.debug = !g.debug
 	}
+	// cycle through the atlas pages, then hide them
+	if Debug && inpututil.IsKeyJustPressed(ebiten.KeyA) {
+		g.atlasPage = (g.atlasPage + 1) % (len(atlas.Pages()) + 1)
+	}
 
 	if g.state == StateMenu {
-		g.space.Update()
+		g.titleFade.Update()
 
 		if len(g.robots) < 4 && math.Mod(g.timer, NewEnemyRate) == 0 {
 			robotType := g.level.NextEnemy()
@@ -126,7 +217,13 @@ func (g *Game) Update() error {
 		}
 
 		for _, pop := range g.pops {
-			pop.Update()
+			pop.Update(g)
+		}
+
+		g.particles.Update()
+
+		for _, popup := range g.popups {
+			popup.Update()
 		}
 
 		for _, fruit := range g.fruits {
@@ -148,6 +245,9 @@ func (g *Game) Update() error {
 		if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
 			g.Start()
 		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			g.OpenOptions()
+		}
 		return nil
 	}
 	if g.state == StatePlaying {
@@ -170,14 +270,19 @@ func (g *Game) Update() error {
 
 			// instant game over
 			if inpututil.IsKeyJustPressed(ebiten.KeyO) {
-				g.state = StateGameOver
+				g.GameOver()
 			}
 		}
 
-		if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		if inpututil.IsKeyJustPressed(g.settings.Key(ActionPause)) {
 			g.state = StatePaused
 		}
 
+		g.level.Update()
+		if g.shakeTween != nil {
+			g.shakeTween.Update()
+		}
+
 		// count the enemies in game
 		enemyCount := 0
 		for _, robot := range g.robots {
@@ -219,7 +324,13 @@ func (g *Game) Update() error {
 		}
 
 		for _, pop := range g.pops {
-			pop.Update()
+			pop.Update(g)
+		}
+
+		g.particles.Update()
+
+		for _, popup := range g.popups {
+			popup.Update()
 		}
 
 		for _, fruit := range g.fruits {
@@ -241,10 +352,10 @@ func (g *Game) Update() error {
 		dx := 0.0
 		// player actions
 		if g.player.CanMove() {
-			if ebiten.IsKeyPressed(ebiten.KeyLeft) {
+			if ebiten.IsKeyPressed(g.settings.Key(ActionLeft)) {
 				dx = -1
 			}
-			if ebiten.IsKeyPressed(ebiten.KeyRight) {
+			if ebiten.IsKeyPressed(g.settings.Key(ActionRight)) {
 				dx = 1
 			}
 			if dx != 0 {
@@ -252,18 +363,19 @@ func (g *Game) Update() error {
 			} else {
 				g.player.Still()
 			}
-			if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+			if inpututil.IsKeyJustPressed(g.settings.Key(ActionJump)) {
 				if g.player.Jump() {
-					g.SoundEffect(sounds[soundJump])
+					g.SoundEffect(SoundJump0, g.player.sprite.X(lib.XCentre), g.player.sprite.Y(lib.YCentre))
 				}
 			}
-			if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+			blowKey := g.settings.Key(ActionBlow)
+			if inpututil.IsKeyJustPressed(blowKey) {
 				g.player.StartBlowing(g)
 			}
-			if inpututil.KeyPressDuration(ebiten.KeySpace) > 1 && inpututil.KeyPressDuration(ebiten.KeySpace) <= MaxBlowingTime {
+			if inpututil.KeyPressDuration(blowKey) > 1 && inpututil.KeyPressDuration(blowKey) <= MaxBlowingTime {
 				g.player.Blowing(g)
 			}
-			if inpututil.IsKeyJustReleased(ebiten.KeySpace) || inpututil.KeyPressDuration(ebiten.KeySpace) > MaxBlowingTime {
+			if inpututil.IsKeyJustReleased(blowKey) || inpututil.KeyPressDuration(blowKey) > MaxBlowingTime {
 				g.player.StopBlowing(g)
 			}
 		}
@@ -274,9 +386,17 @@ func (g *Game) Update() error {
 
 	if g.state == StatePaused {
 		// un-pause
-		if inpututil.IsKeyJustPressed(ebiten.KeyP) || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		if inpututil.IsKeyJustPressed(g.settings.Key(ActionPause)) || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
 			g.state = StatePlaying
 		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			g.OpenOptions()
+		}
+		return nil
+	}
+
+	if g.state == StateOptions {
+		g.state = g.options.Update()
 		return nil
 	}
 
@@ -293,59 +413,72 @@ func (g *Game) Update() error {
 
 // Draw game events
 func (g *Game) Draw(screen *ebiten.Image) {
-
-	g.level.Draw(screen)
-
-	for _, fruit := range g.fruits {
-		fruit.Draw(screen)
-	}
-
-	for _, bolt := range g.bolts {
-		bolt.Draw(screen)
-	}
-
-	for _, pop := range g.pops {
-		pop.Draw(screen)
+	// the atlas pages cover the whole screen
+	g.layers.Layer(LayerHUD).SetVisible(g.atlasPage == 0)
+	g.layers.Layer(LayerOverlay).SetVisible(g.atlasPage == 0)
+	// how far we are into the tick, to draw the sprites between their previous and current positions
+	lib.SetTickProgress(time.Since(g.tickTime).Seconds() * float64(ebiten.TPS()))
+
+	if g.canvas == nil {
+		g.canvas = ebiten.NewImage(WindowWidth, WindowHeight)
 	}
-
-	for _, robot := range g.robots {
-		robot.Draw(screen)
-	}
-
-	for _, orb := range g.orbs {
-		orb.Draw(screen)
-	}
-
-	g.player.Draw(screen)
-
-	if g.debug {
-		g.displayDebug(screen)
-	}
-
-	if g.state == StateMenu {
-		screen.DrawImage(images[imageTitle], nil)
-		g.space.Draw(screen)
-		return
+	g.canvas.Clear()
+	if g.postProcess.Enabled() {
+		if g.frame == nil {
+			g.frame = ebiten.NewImage(WindowWidth, WindowHeight)
+		}
+		g.frame.Clear()
+		g.layers.Draw(g.frame)
+		g.postProcess.Draw(g.canvas, g.frame)
+	} else {
+		g.layers.Draw(g.canvas)
 	}
+	g.viewport.Draw(screen, g.canvas)
+}
 
-	if g.state == StateGameOver {
-		screen.DrawImage(images[imageOver], nil)
-		return
+// musicTrack returns the music track to play in the current state of the game
+func (g *Game) musicTrack() string {
+	switch g.state {
+	case StateMenu:
+		return musicTitle
+	case StateGameOver:
+		if g.newHighScore {
+			return musicHighScore
+		}
+		return musicOver
+	case StateOptions:
+		// keep the music playing before the options screen was opened
+		return g.mixer.MusicTrack()
+	default:
+		if g.level.Hurry() {
+			return musicHurry
+		}
+		return g.level.Music()
 	}
 }
 
-// SoundEffect plays a sound in the game
-func (g *Game) SoundEffect(se []byte) {
-	PlaySE(g.audioContext, se)
+// SoundEffect plays a sound in the game, coming from the x and y coordinates on the screen
+func (g *Game) SoundEffect(name SoundName, x, y float64) {
+	g.mixer.Play(Sound(name), panning(x), attenuation(x, y))
 }
 
-// RandomSoundEffect plays a random sound effect from a list
-func (g *Game) RandomSoundEffect(sounds [][]byte) {
+// RandomSoundEffect plays a random sound effect from a list, coming from the x and y coordinates on the screen
+func (g *Game) RandomSoundEffect(sounds []SoundName, x, y float64) {
 	if sounds == nil || len(sounds) == 0 {
 		return
 	}
 	soundID := rand.Intn(len(sounds))
-	PlaySE(g.audioContext, sounds[soundID])
+	g.SoundEffect(sounds[soundID], x, y)
+}
+
+// EventSound plays a random sound of the group named by an animation event starting with the EventSound prefix,
+// coming from the x and y coordinates on the screen. Other events are ignored.
+func (g *Game) EventSound(event string, x, y float64) {
+	group, found := strings.CutPrefix(event, EventSound)
+	if !found {
+		return
+	}
+	g.RandomSoundEffect(SoundGroup(group), x, y)
 }
 
 func (g *Game) CreateFruit(extra bool) *Fruit {
@@ -357,6 +490,7 @@ func (g *Game) CreateFruit(extra bool) *Fruit {
 	}
 	fruit := NewFruit(g.level, extra)
 	g.fruits = append(g.fruits, fruit)
+	g.layers.Add(LayerWorld, fruit, ZFruit)
 	return fruit
 }
 
@@ -368,7 +502,9 @@ func (g *Game) CreateRobot(robotType RobotType) {
 			return
 		}
 	}
-	g.robots = append(g.robots, NewRobot(g.level).Generate(robotType))
+	robot := NewRobot(g.level).Generate(robotType)
+	g.robots = append(g.robots, robot)
+	g.layers.Add(LayerWorld, robot, ZRobot)
 }
 
 func (g *Game) StartPop(popType PopType, x, y float64) {
@@ -383,6 +519,36 @@ func (g *Game) StartPop(popType PopType, x, y float64) {
 	pop := NewPop()
 	pop.Start(popType, x, y)
 	g.pops = append(g.pops, pop)
+	g.layers.Add(LayerWorld, pop, ZPop)
+}
+
+// Shake the camera for a short while
+func (g *Game) Shake() {
+	world := g.layers.Layer(LayerWorld)
+	world.SetEffect(g.shakeEffect)
+	g.shakeTween = tween.Float(&g.shake, ShakeAmplitude, 0, ShakeTime, tween.QuadOut).OnComplete(func() {
+		world.SetEffect(nil)
+	})
+}
+
+// shakeOffset returns the offset of the camera while it shakes
+func (g *Game) shakeOffset() (float64, float64) {
+	return g.shake * math.Sin(g.timer*1.9), g.shake * math.Cos(g.timer*2.7)
+}
+
+// StartPopup shows a text floating up from the x and y coordinates
+func (g *Game) StartPopup(text string, x, y float64) {
+	// find a free popup
+	for _, popup := range g.popups {
+		if popup.HasExpired() {
+			popup.Start(text, x, y)
+			return
+		}
+	}
+	// we need a new one
+	popup := NewPopup().Start(text, x, y)
+	g.popups = append(g.popups, popup)
+	g.layers.Add(LayerWorld, popup, ZPopup)
 }
 
 // NewOrb creates a new orb
@@ -406,7 +572,9 @@ func (g *Game) Fire(directionX, x, y float64) {
 		}
 	}
 	// otherwise create a new one
-	g.bolts = append(g.bolts, NewBolt(g.level).Fire(directionX, x, y))
+	bolt := NewBolt(g.level).Fire(directionX, x, y)
+	g.bolts = append(g.bolts, bolt)
+	g.layers.Add(LayerWorld, bolt, ZBolt)
 }
 
 func (g *Game) Orbs() []*Orb {
@@ -422,40 +590,3 @@ func (g *Game) ActiveOrbs() []*Orb {
 	}
 	return orbs
 }
-
-// CharWidth returns width of given character. For characters other than the letters A to Z (i.e. space, and the digits 0 to 9),
-// the width of the letter A is returned.
-func CharWidth(char byte) int {
-	i := int(char) - 65
-	if i < 0 {
-		i = 0
-	}
-	if i >= len(CharWidths) {
-		log.Printf("character '%c'(%d) not in font", char, char)
-	}
-	return CharWidths[i]
-}
-
-func DrawTextCentre(screen *ebiten.Image, text []byte, y float64) {
-	width := 0
-	for _, c := range text {
-		width += CharWidth(c)
-	}
-	x := (WindowWidth - width) / 2
-	DrawText(screen, text, float64(x), y)
-}
-
-func DrawText(screen *ebiten.Image, text []byte, x, y float64) {
-	op := &ebiten.DrawImageOptions{}
-	for _, char := range text {
-		image := images[fmt.Sprintf("font0%d", char)]
-		if image == nil {
-			log.Printf("character '%c'(%d) not available in font", char, char)
-			return
-		}
-		op.GeoM.Reset()
-		op.GeoM.Translate(x, y)
-		screen.DrawImage(image, op)
-		x += float64(CharWidth(char))
-	}
-}
@@ -0,0 +1,121 @@
+// Package i18n translates the messages displayed in the game.
+//
+// Each language is a JSON file of messages, named after the language code (en.json, fr.json, ...).
+// A message missing from a language is looked up in the parent language (fr for fr-CA),
+// then in the default language, and finally the message key is returned unchanged.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// NameKey is the message containing the name of the language, in that language
+const NameKey = "language"
+
+// Catalogue contains the messages of all the languages
+type Catalogue struct {
+	messages        map[string]map[string]string
+	defaultLanguage string
+	language        string
+}
+
+// Load all the language files matching the pattern from the file system.
+// The default language is used when a message is missing from the current language.
+func Load(fsys fs.FS, pattern, defaultLanguage string) (*Catalogue, error) {
+	filenames, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalogue{
+		messages:        make(map[string]map[string]string, len(filenames)),
+		defaultLanguage: defaultLanguage,
+		language:        defaultLanguage,
+	}
+	for _, filename := range filenames {
+		data, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return nil, err
+		}
+		messages := make(map[string]string)
+		err = json.Unmarshal(data, &messages)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		language := strings.TrimSuffix(path.Base(filename), path.Ext(filename))
+		c.messages[language] = messages
+	}
+	if _, found := c.messages[defaultLanguage]; !found {
+		return nil, fmt.Errorf("default language %q not found", defaultLanguage)
+	}
+	return c, nil
+}
+
+// Languages returns the codes of all the languages available, sorted
+func (c *Catalogue) Languages() []string {
+	languages := make([]string, 0, len(c.messages))
+	for language := range c.messages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// SetLanguage changes the current language. An empty or unknown language falls back to the parent or default language
+func (c *Catalogue) SetLanguage(language string) {
+	c.language = c.resolve(language)
+}
+
+// Language returns the current language
+func (c *Catalogue) Language() string {
+	return c.language
+}
+
+// Name returns the name of the language, in that language
+func (c *Catalogue) Name(language string) string {
+	if name, found := c.messages[language][NameKey]; found {
+		return name
+	}
+	return language
+}
+
+// T returns the message translated in the current language. When arguments are given, the message is used as a format.
+func (c *Catalogue) T(key string, args ...interface{}) string {
+	message := c.lookup(key)
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+func (c *Catalogue) lookup(key string) string {
+	for _, language := range []string{c.language, parent(c.language), c.defaultLanguage} {
+		if message, found := c.messages[language][key]; found {
+			return message
+		}
+	}
+	return key
+}
+
+// resolve returns the closest language available
+func (c *Catalogue) resolve(language string) string {
+	language = strings.ReplaceAll(language, "_", "-")
+	for _, candidate := range []string{language, parent(language)} {
+		if _, found := c.messages[candidate]; found {
+			return candidate
+		}
+	}
+	return c.defaultLanguage
+}
+
+// parent returns the generic language of a regional language (fr for fr-CA)
+func parent(language string) string {
+	if i := strings.IndexByte(language, '-'); i > 0 {
+		return language[:i]
+	}
+	return language
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFiles = fstest.MapFS{
+	"lang/en.json":    {Data: []byte(`{"language": "English", "level": "LEVEL %d", "pause": "PAUSED", "back": "BACK"}`)},
+	"lang/fr.json":    {Data: []byte(`{"language": "Français", "level": "NIVEAU %d", "pause": "PAUSE"}`)},
+	"lang/fr-CA.json": {Data: []byte(`{"pause": "EN PAUSE"}`)},
+}
+
+func TestTranslate(t *testing.T) {
+	catalogue, err := Load(testFiles, "lang/*.json", "en")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "fr", "fr-CA"}, catalogue.Languages())
+
+	testData := []struct {
+		language string
+		current  string
+		level    string
+		pause    string
+		back     string
+	}{
+		{"", "en", "LEVEL 2", "PAUSED", "BACK"},
+		{"en", "en", "LEVEL 2", "PAUSED", "BACK"},
+		{"fr", "fr", "NIVEAU 2", "PAUSE", "BACK"},
+		{"fr-CA", "fr-CA", "NIVEAU 2", "EN PAUSE", "BACK"},
+		{"fr_BE", "fr", "NIVEAU 2", "PAUSE", "BACK"},
+		{"de", "en", "LEVEL 2", "PAUSED", "BACK"},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.language, func(t *testing.T) {
+			catalogue.SetLanguage(testItem.language)
+			assert.Equal(t, testItem.current, catalogue.Language())
+			assert.Equal(t, testItem.level, catalogue.T("level", 2))
+			assert.Equal(t, testItem.pause, catalogue.T("pause"))
+			assert.Equal(t, testItem.back, catalogue.T("back"))
+			assert.Equal(t, "missing", catalogue.T("missing"))
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	catalogue, err := Load(testFiles, "lang/*.json", "en")
+	require.NoError(t, err)
+	assert.Equal(t, "Français", catalogue.Name("fr"))
+	assert.Equal(t, "fr-CA", catalogue.Name("fr-CA"))
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(testFiles, "lang/*.json", "de")
+	assert.Error(t, err)
+
+	_, err = Load(fstest.MapFS{"en.json": {Data: []byte(`{"level": 1}`)}}, "*.json", "en")
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// every language file should translate all the messages of the default language
+func TestLanguageFilesComplete(t *testing.T) {
+	readMessages := func(filename string) map[string]string {
+		data, err := fs.ReadFile(embededFiles, filename)
+		require.NoError(t, err)
+		messages := make(map[string]string)
+		require.NoError(t, json.Unmarshal(data, &messages))
+		return messages
+	}
+	reference := readMessages("lang/" + defaultLanguage + ".json")
+
+	filenames, err := fs.Glob(embededFiles, "lang/*.json")
+	require.NoError(t, err)
+	for _, filename := range filenames {
+		t.Run(filename, func(t *testing.T) {
+			messages := readMessages(filename)
+			for key := range reference {
+				assert.NotEmpty(t, messages[key], key)
+			}
+			for key := range messages {
+				assert.Contains(t, reference, key)
+			}
+		})
+	}
+}
+
+// every message should be drawn with the TrueType fonts, or entirely with the fallback glyphs
+func TestLanguageFilesDrawable(t *testing.T) {
+	renderer, err := loadTextRenderer()
+	require.NoError(t, err)
+	fallback, err := loadFallbackFace()
+	require.NoError(t, err)
+
+	filenames, err := fs.Glob(embededFiles, "lang/*.json")
+	require.NoError(t, err)
+	for _, filename := range filenames {
+		t.Run(filename, func(t *testing.T) {
+			data, err := fs.ReadFile(embededFiles, filename)
+			require.NoError(t, err)
+			messages := make(map[string]string)
+			require.NoError(t, json.Unmarshal(data, &messages))
+			for key, message := range messages {
+				assert.True(t, renderer.Covers(message) || fallback.Covers(message), "%s: missing glyphs in %q, run go generate", key, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math"
+
+	"github.com/creativeprojects/cavern/lib"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Layers, from the bottom to the top
+const (
+	LayerWorld   = "world"   // the level and everything moving in it
+	LayerDebug   = "debug"   // debug information (debug build only)
+	LayerHUD     = "hud"     // level banner, score, health and lives
+	LayerOverlay = "overlay" // title, pause, game over and options screens
+)
+
+// Z values of the world layer: the highest is drawn on top
+const (
+	ZLevel     = 0
+	ZFruit     = 10
+	ZBolt      = 20
+	ZPop       = 30
+	ZRobot     = 40
+	ZOrb       = 50
+	ZPlayer    = 60
+	ZParticles = 70
+	ZPopup     = 80
+)
+
+// newLayers creates the layers with the screens that don't depend on a game
+func (g *Game) newLayers() *lib.Layers {
+	layers := lib.NewLayers(LayerWorld, LayerDebug, LayerHUD, LayerOverlay)
+	layers.Add(LayerDebug, lib.DrawFunc(g.drawDebug), 0)
+	layers.Add(LayerHUD, lib.DrawFunc(g.drawHUD), 0)
+	layers.Add(LayerOverlay, lib.DrawFunc(g.drawOverlay), 0)
+	return layers
+}
+
+// shakeEffect draws the world layer at the offset of the camera shake
+func (g *Game) shakeEffect(dst, src *ebiten.Image) {
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(g.shakeOffset())
+	dst.DrawImage(src, op)
+}
+
+// drawDebug displays the debug information and the atlas pages
+func (g *Game) drawDebug(screen *ebiten.Image) {
+	if g.debug {
+		g.displayDebug(screen)
+	}
+	if g.atlasPage > 0 {
+		g.displayAtlas(screen, g.atlasPage-1)
+	}
+}
+
+// drawHUD displays the level banner, the score and the indicators: they don't shake with the world
+func (g *Game) drawHUD(screen *ebiten.Image) {
+	g.level.DrawBanner(screen)
+	g.player.DrawHUD(screen)
+
+	if g.mixer.IsMuted() {
+		textRenderer.Draw(screen, T("muted"), WindowWidth-10, 10, hudRightStyle)
+	}
+}
+
+// drawOverlay displays the screen of the current state on top of the game
+func (g *Game) drawOverlay(screen *ebiten.Image) {
+	switch g.state {
+	case StateOptions:
+		g.options.Draw(screen)
+
+	case StateMenu:
+		op := &ebiten.DrawImageOptions{}
+		op.ColorScale.ScaleAlpha(float32(g.titleAlpha))
+		screen.DrawImage(Image(ImageTitle), op)
+		// "Press SPACE" flashes for a quarter of the time, every 160 frames.
+		// Adding 40 to the game timer is done to alter which stage the animation is at when the game first starts
+		if math.Mod(g.timer+40, 160) >= 40 || math.Mod(g.timer, 8) < 4 {
+			textRenderer.Draw(screen, T("press_space"), WindowWidth/2, 300, pressSpaceStyle)
+		}
+		if g.highScore > 0 {
+			textRenderer.Draw(screen, T("high_score", g.highScore), WindowWidth/2, 250, hudStyle)
+		}
+		textRenderer.Draw(screen, T("options_hint"), WindowWidth/2, 440, hudStyle)
+
+	case StatePaused:
+		textRenderer.Draw(screen, T("paused"), WindowWidth/2, 180, titleStyle)
+		textRenderer.Draw(screen, T("options_hint"), WindowWidth/2, 260, hudStyle)
+
+	case StateGameOver:
+		vector.DrawFilledRect(screen, 0, 0, WindowWidth, WindowHeight, gameOverBackground, false)
+		textRenderer.Draw(screen, T("game_over"), WindowWidth/2, 200, gameOverStyle)
+		if g.newHighScore {
+			textRenderer.Draw(screen, T("new_high_score"), WindowWidth/2, 290, titleStyle)
+		}
+	}
+}
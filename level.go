@@ -1,55 +1,103 @@
 package main
 
 import (
-	"fmt"
+	"log"
 	"math"
 	"math/rand"
 
+	"github.com/creativeprojects/cavern/lib"
+	"github.com/creativeprojects/cavern/lib/tween"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-const (
-	totalLevels = 4
-)
-
 type Level struct {
-	backgroundImages [totalLevels]*ebiten.Image
-	blockImages      [totalLevels]*ebiten.Image
-	op               *ebiten.DrawImageOptions
-	id               int
-	colour           int
-	grid             []string
-	pendingEnemies   []RobotType
+	image          *ebiten.Image // background and blocks, drawn again when the level or its theme changes
+	redraw         bool
+	op             *ebiten.DrawRectShaderOptions
+	id             int
+	theme          *Theme
+	themeName      string
+	grid           []string
+	music          string
+	timer          int
+	bannerX        float64 // centre of the "LEVEL n" banner
+	bannerSlide    *tween.Tween
+	pendingEnemies []RobotType
+	difficulty     Difficulty
 }
 
 // NewLevel creates an empty level. Please call Next() to load the first level
-func NewLevel() *Level {
+func NewLevel(difficulty Difficulty) *Level {
 	return &Level{
-		id:     -1,
-		colour: -1,
-		backgroundImages: [totalLevels]*ebiten.Image{
-			images["bg0"],
-			images["bg1"],
-			images["bg2"],
-			images["bg3"],
-		},
-		blockImages: [totalLevels]*ebiten.Image{
-			images["block0"],
-			images["block1"],
-			images["block2"],
-			images["block3"],
-		},
-		op: &ebiten.DrawImageOptions{},
-	}
-}
-
-// Next changes the color and loads the grid for the next level
+		id:         -1,
+		difficulty: difficulty,
+		op:         &ebiten.DrawRectShaderOptions{},
+	}
+}
+
+// Next loads the grid and theme of the next level
 func (l *Level) Next() {
 	l.id++
-	l.colour = int(math.Mod(float64(l.colour+1), 4))
-	gridID := int(math.Mod(float64(l.id), float64(len(LevelsDefinition))))
-	l.grid = append(LevelsDefinition[gridID], LevelsDefinition[gridID][0])
+	l.loadGrid()
+	l.timer = 0
 	l.createPendingEnemies()
+	// the banner slides in from the right
+	l.bannerX = WindowWidth * 1.5
+	l.bannerSlide = tween.Float(&l.bannerX, l.bannerX, WindowWidth/2, BannerSlideTime, tween.BackOut)
+}
+
+// Reload the grid and theme of the current level from its definition, which may have changed since the level started
+func (l *Level) Reload() {
+	if l.id < 0 {
+		return
+	}
+	l.loadGrid()
+}
+
+// Redraw the background and blocks on the next frame, after their images or the palette shader changed
+func (l *Level) Redraw() {
+	l.redraw = true
+}
+
+// loadGrid loads the grid, theme and music of the current level
+func (l *Level) loadGrid() {
+	gridID := int(math.Mod(float64(l.id), float64(len(LevelsDefinition))))
+	definition := LevelsDefinition[gridID]
+	// the bottom row is a copy of the top row
+	l.grid = make([]string, 0, len(definition.Grid)+1)
+	l.grid = append(l.grid, definition.Grid...)
+	l.grid = append(l.grid, definition.Grid[0])
+	l.music = definition.Music
+	if l.music == "" {
+		l.music = musicGame
+	}
+	l.themeName = definition.Theme
+	if l.themeName == "" {
+		l.themeName = themePresets[l.id%len(themePresets)]
+	}
+	l.theme = themes[l.themeName]
+	if l.theme == nil {
+		log.Printf("level %d: theme %q not found", l.id+1, l.themeName)
+		l.themeName = themePresets[0]
+		l.theme = themes[l.themeName]
+	}
+	l.redraw = true
+}
+
+// Update the time spent in the level
+func (l *Level) Update() {
+	l.timer++
+	l.bannerSlide.Update()
+}
+
+// Hurry returns true when the player has been on this level for too long
+func (l *Level) Hurry() bool {
+	return l.timer > LevelHurryTime
+}
+
+// Music returns the music track of the level
+func (l *Level) Music() string {
+	return l.music
 }
 
 // ID is the current level number (starting at zero)
@@ -59,24 +107,58 @@ func (l *Level) ID() int {
 
 // Draw level
 func (l *Level) Draw(screen *ebiten.Image) {
-	if l.colour < 0 || l.colour > 3 {
+	if l.theme == nil {
 		return
 	}
-	screen.DrawImage(l.backgroundImages[l.colour], nil)
+	if l.redraw {
+		l.draw()
+		l.redraw = false
+	}
+	screen.DrawImage(l.image, nil)
+}
 
+// DrawBanner draws the "LEVEL n" banner at the bottom of the screen
+func (l *Level) DrawBanner(screen *ebiten.Image) {
+	banner := T("level", l.id+1)
+	if bitmapFont.Covers(banner) {
+		bitmapFont.Draw(screen, banner, l.bannerX, 451, lib.XCentre, nil)
+	} else {
+		// the bitmap font only has ASCII characters
+		textRenderer.Draw(screen, banner, l.bannerX, 451, bannerStyle)
+	}
+}
+
+// draw the background and the blocks with the colours of the theme
+func (l *Level) draw() {
+	if l.image == nil {
+		l.image = ebiten.NewImage(WindowWidth, WindowHeight)
+	}
+	l.image.Clear()
+	l.op.Uniforms = l.theme.uniforms()
+
+	l.drawPattern(Image(l.theme.BackgroundPattern), 0, 0)
+	block := Image(l.theme.BlockPattern)
 	for y, line := range l.grid {
 		x := LeftGridOffset
 		for _, char := range line {
 			if char != ' ' {
-				l.op.GeoM.Reset()
-				l.op.GeoM.Translate(x, float64(y)*GridBlockSize)
-				screen.DrawImage(l.blockImages[l.colour], l.op)
+				l.drawPattern(block, x, float64(y)*GridBlockSize)
 			}
 			x += GridBlockSize
 		}
 	}
-	// Level
-	DrawTextCentre(screen, []byte(fmt.Sprintf("LEVEL %d", l.id+1)), 451)
+}
+
+// drawPattern draws the image at the coordinates, recoloured with the theme when it has colours
+func (l *Level) drawPattern(pattern *ebiten.Image, x, y float64) {
+	l.op.GeoM.Reset()
+	l.op.GeoM.Translate(x, y)
+	if !l.theme.recoloured() {
+		l.image.DrawImage(pattern, &ebiten.DrawImageOptions{GeoM: l.op.GeoM})
+		return
+	}
+	l.op.Images[0] = pattern
+	l.image.DrawRectShader(pattern.Bounds().Dx(), pattern.Bounds().Dy(), shaders[ShaderPalette], l.op)
 }
 
 // Block returns true if there's a grid block at these coordinates
@@ -97,7 +179,7 @@ func (l *Level) MaxEnemies() int {
 
 // FireProbability returns the likehood per frame of each robot firing a bolt
 func (l *Level) FireProbability() float64 {
-	return 0.001 + (0.0001 * math.Min(100, float64(l.id)))
+	return (0.001 + (0.0001 * math.Min(100, float64(l.id)))) * l.difficulty.FireFactor()
 }
 
 // NextEnemy returns the type of the next enemy to create, if any
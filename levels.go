@@ -1,38 +1,57 @@
 package main
 
-var (
-	LevelsDefinition = [...][]string{
-		{
-			"XXXXX     XXXXXXXX     XXXXX",
-			"", "", "", "",
-			"   XXXXXXX        XXXXXXX   ",
-			"", "", "",
-			"   XXXXXXXXXXXXXXXXXXXXXX   ",
-			"", "", "",
-			"XXXXXXXXX          XXXXXXXXX",
-			"", "", "",
-		},
-		{
-			"XXXX    XXXXXXXXXXXX    XXXX",
-			"", "", "", "",
-			"    XXXXXXXXXXXXXXXXXXXX    ",
-			"", "", "",
-			"XXXXXX                XXXXXX",
-			"      X              X      ",
-			"       X            X       ",
-			"        X          X        ",
-			"         X        X         ",
-			"", "", "",
-		},
-		{
-			"XXXX    XXXX    XXXX    XXXX",
-			"", "", "", "",
-			"  XXXXXXXX        XXXXXXXX  ",
-			"", "", "",
-			"XXXX      XXXXXXXX      XXXX",
-			"", "", "",
-			"    XXXXXX        XXXXXX    ",
-			"", "", "",
-		},
-	}
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
 )
+
+// LevelDefinition describes the grid of a level, its colours and the music track played during the level
+type LevelDefinition struct {
+	Grid  []string `json:"grid"`  // one line per row from the top of the screen: a space is empty, any other character is a block
+	Music string   `json:"music"` // the default game music when empty
+	Theme string   `json:"theme"` // name of a theme in the themes folder, the next preset when empty
+}
+
+// LevelsDefinition contains all the levels, in the order they're played
+var LevelsDefinition []LevelDefinition
+
+// loadLevels loads all the level files (levels/*.json), sorted by name
+func loadLevels(fsys fs.FS) ([]LevelDefinition, error) {
+	filenames, err := fs.Glob(fsys, "levels/*.json")
+	if err != nil {
+		return nil, err
+	}
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("no level found")
+	}
+	levels := make([]LevelDefinition, len(filenames))
+	for i, filename := range filenames {
+		data, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &levels[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		err = levels[i].validate()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+	return levels, nil
+}
+
+// validate checks the grid fits on the screen. The last row is a copy of the first one, so it's not in the definition.
+func (d LevelDefinition) validate() error {
+	if len(d.Grid) != NumRows-1 {
+		return fmt.Errorf("the grid should have %d rows but has %d", NumRows-1, len(d.Grid))
+	}
+	for i, row := range d.Grid {
+		if row != "" && len(row) != NumColumns {
+			return fmt.Errorf("row %d should be empty or have %d characters but has %d", i+1, NumColumns, len(row))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEmbeddedLevels(t *testing.T) {
+	levels, err := loadLevels(embededFiles)
+	require.NoError(t, err)
+	assert.NotEmpty(t, levels)
+}
+
+func TestLoadInvalidLevels(t *testing.T) {
+	validGrid := `"` + strings.Repeat("X", NumColumns) + `"` + strings.Repeat(`, ""`, NumRows-2)
+	testData := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{"valid", `{"grid": [` + validGrid + `]}`, true},
+		{"invalid JSON", `{"grid": [`, false},
+		{"missing rows", `{"grid": ["` + strings.Repeat("X", NumColumns) + `"]}`, false},
+		{"short row", `{"grid": ["XXX"` + strings.Repeat(`, ""`, NumRows-2) + `]}`, false},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"levels/level1.json": {Data: []byte(testItem.content)},
+			}
+			levels, err := loadLevels(fsys)
+			if testItem.valid {
+				require.NoError(t, err)
+				assert.Len(t, levels, 1)
+				return
+			}
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := loadLevels(fstest.MapFS{})
+	assert.Error(t, err)
+}
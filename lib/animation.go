@@ -0,0 +1,193 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Frame is an image of an animation, displayed for a number of ticks
+type Frame struct {
+	Image    *ebiten.Image
+	Duration int
+	Hitbox   *Hitbox // nil to use the hitbox of the sprite
+}
+
+// EventDone is sent by the Sprite when an animation which doesn't loop is finished
+const EventDone = "done"
+
+// AnimationEvent is a named event happening when the animation reaches a frame
+type AnimationEvent struct {
+	Frame int    `json:"frame"`
+	Name  string `json:"name"`
+	Once  bool   `json:"once"` // sent the first time the frame is reached only, not again when the animation loops
+}
+
+// Animation is a list of frames played by a Sprite
+type Animation struct {
+	Name     string
+	Frames   []Frame
+	Mode     PlayMode         // order the frames are played
+	FlipX    bool             // mirror the images horizontally
+	FlipY    bool             // mirror the images vertically
+	Loop     bool             // play again once finished
+	LoopFrom int              // position to go back to when looping, counted in the frames played (the same as the frame index when playing forward)
+	Events   []AnimationEvent // sorted by frame
+	revision int              // incremented each time the animation is reloaded
+}
+
+// NewAnimation creates an animation showing each image for the same number of ticks
+func NewAnimation(name string, images []*ebiten.Image, duration int, loop bool) *Animation {
+	frames := make([]Frame, len(images))
+	for i, image := range images {
+		frames[i] = Frame{Image: image, Duration: duration}
+	}
+	return &Animation{
+		Name:   name,
+		Frames: frames,
+		Loop:   loop,
+	}
+}
+
+// steps returns the number of frames played before finishing or looping
+func (a *Animation) steps() int {
+	count := len(a.Frames)
+	if a.Mode != PingPong || count < 2 {
+		return count
+	}
+	if a.Loop {
+		// the first frame is played again when looping
+		return 2*count - 2
+	}
+	return 2*count - 1
+}
+
+// frameAt returns the index of the frame played at the step
+func (a *Animation) frameAt(step int) int {
+	count := len(a.Frames)
+	switch {
+	case a.Mode == Reverse:
+		return count - 1 - step
+	case a.Mode == PingPong && step >= count:
+		return 2*count - 2 - step
+	default:
+		return step
+	}
+}
+
+// Reload replaces the definition of the animation with another one, in place:
+// the sprites playing the animation carry on with the new frames, from the first frame if they were past the last one
+func (a *Animation) Reload(from *Animation) {
+	revision := a.revision + 1
+	*a = *from
+	a.revision = revision
+}
+
+// eventsAt calls the callback with the name of each event on the frame. Once the animation has looped, the events sent once are skipped.
+func (a *Animation) eventsAt(frame int, looped bool, callback func(name string)) {
+	for _, event := range a.Events {
+		if event.Frame == frame && !(looped && event.Once) {
+			callback(event.Name)
+		}
+	}
+}
+
+// Length returns the total number of ticks of the animation (the first time it's played)
+func (a *Animation) Length() int {
+	length := 0
+	for step := 0; step < a.steps(); step++ {
+		length += a.Frames[a.frameAt(step)].Duration
+	}
+	return length
+}
+
+// AnimationDefinition describes an animation in a data file, using image names
+type AnimationDefinition struct {
+	Frames    []string         `json:"frames"`    // name of the image of each frame
+	Duration  int              `json:"duration"`  // number of ticks each frame is displayed
+	Durations []int            `json:"durations"` // number of ticks of each frame, instead of the same duration for all
+	Mode      PlayMode         `json:"mode"`      // "forward" (by default), "reverse" or "ping-pong"
+	FlipX     bool             `json:"flip_x"`    // mirror the images horizontally (around the sprite position)
+	FlipY     bool             `json:"flip_y"`    // mirror the images vertically
+	Hitbox    *Hitbox          `json:"hitbox"`    // hitbox of all the frames (relative to the sprite position, before flipping)
+	Hitboxes  []*Hitbox        `json:"hitboxes"`  // hitbox of each frame, instead of the same hitbox for all (null for the hitbox of all the frames)
+	Loop      bool             `json:"loop"`      // the animation plays only once when false
+	LoopFrom  int              `json:"loop_from"` // position in the frames played to go back to when looping
+	Events    []AnimationEvent `json:"events"`
+}
+
+// LoadAnimations reads the animation definitions from a JSON object (animation name: definition).
+// The images function returns the image from its name, or nil when the image doesn't exist.
+func LoadAnimations(reader io.Reader, images func(name string) *ebiten.Image) (map[string]*Animation, error) {
+	definitions := make(map[string]AnimationDefinition)
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&definitions)
+	if err != nil {
+		return nil, err
+	}
+	animations := make(map[string]*Animation, len(definitions))
+	for name, definition := range definitions {
+		animation, err := definition.build(name, images)
+		if err != nil {
+			return nil, fmt.Errorf("animation %q: %w", name, err)
+		}
+		animations[name] = animation
+	}
+	return animations, nil
+}
+
+func (d AnimationDefinition) build(name string, images func(name string) *ebiten.Image) (*Animation, error) {
+	if len(d.Frames) == 0 {
+		return nil, fmt.Errorf("no frame")
+	}
+	if len(d.Durations) > 0 && len(d.Durations) != len(d.Frames) {
+		return nil, fmt.Errorf("%d durations for %d frames", len(d.Durations), len(d.Frames))
+	}
+	if len(d.Hitboxes) > 0 && len(d.Hitboxes) != len(d.Frames) {
+		return nil, fmt.Errorf("%d hitboxes for %d frames", len(d.Hitboxes), len(d.Frames))
+	}
+	animation := &Animation{
+		Name:     name,
+		Frames:   make([]Frame, len(d.Frames)),
+		Mode:     d.Mode,
+		FlipX:    d.FlipX,
+		FlipY:    d.FlipY,
+		Loop:     d.Loop,
+		LoopFrom: d.LoopFrom,
+		Events:   d.Events,
+	}
+	for i, imageName := range d.Frames {
+		image := images(imageName)
+		if image == nil {
+			return nil, fmt.Errorf("image %q not found", imageName)
+		}
+		duration := d.Duration
+		if len(d.Durations) > 0 {
+			duration = d.Durations[i]
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("frame %d: duration should be at least one tick", i)
+		}
+		hitbox := d.Hitbox
+		if len(d.Hitboxes) > 0 && d.Hitboxes[i] != nil {
+			hitbox = d.Hitboxes[i]
+		}
+		animation.Frames[i] = Frame{Image: image, Duration: duration, Hitbox: hitbox}
+	}
+	if d.LoopFrom < 0 || d.LoopFrom >= animation.steps() {
+		return nil, fmt.Errorf("loop_from %d is outside of the %d frames played", d.LoopFrom, animation.steps())
+	}
+	for _, event := range animation.Events {
+		if event.Frame < 0 || event.Frame >= len(animation.Frames) {
+			return nil, fmt.Errorf("event %q: frame %d is not in the animation", event.Name, event.Frame)
+		}
+	}
+	sort.SliceStable(animation.Events, func(i, j int) bool {
+		return animation.Events[i].Frame < animation.Events[j].Frame
+	})
+	return animation, nil
+}
@@ -0,0 +1,184 @@
+package lib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testImages returns a lookup function for images that are never drawn
+func testImages(names ...string) (func(string) *ebiten.Image, map[string]*ebiten.Image) {
+	images := make(map[string]*ebiten.Image, len(names))
+	for _, name := range names {
+		images[name] = new(ebiten.Image)
+	}
+	return func(name string) *ebiten.Image {
+		return images[name]
+	}, images
+}
+
+func TestLoadAnimations(t *testing.T) {
+	lookup, images := testImages("a", "b", "c")
+	animations, err := LoadAnimations(strings.NewReader(`{
+		"walk": {"frames": ["a", "b", "c", "b"], "duration": 4, "loop": true},
+		"blow": {"frames": ["a", "b", "c"], "durations": [3, 3, 8], "loop": true, "loop_from": 2},
+		"fire": {"frames": ["c", "a"], "duration": 2, "events": [{"frame": 1, "name": "end"}, {"frame": 0, "name": "start", "once": true}]},
+		"trap": {"frames": ["a", "b", "c"], "duration": 2, "hitbox": {"y": -10, "radius": 8}, "hitboxes": [{"x": -2, "y": -4, "width": 4, "height": 4}, null, null]}
+	}`), lookup)
+	require.NoError(t, err)
+	require.Len(t, animations, 4)
+
+	walk := animations["walk"]
+	assert.Equal(t, "walk", walk.Name)
+	assert.True(t, walk.Loop)
+	assert.Equal(t, []Frame{{Image: images["a"], Duration: 4}, {Image: images["b"], Duration: 4}, {Image: images["c"], Duration: 4}, {Image: images["b"], Duration: 4}}, walk.Frames)
+	assert.Equal(t, 16, walk.Length())
+
+	blow := animations["blow"]
+	assert.Equal(t, 2, blow.LoopFrom)
+	assert.Equal(t, 14, blow.Length())
+
+	fire := animations["fire"]
+	assert.False(t, fire.Loop)
+	assert.Equal(t, []AnimationEvent{{Frame: 0, Name: "start", Once: true}, {Frame: 1, Name: "end"}}, fire.Events)
+	assert.Nil(t, fire.Frames[0].Hitbox)
+
+	trap := animations["trap"]
+	assert.Equal(t, &Hitbox{X: -2, Y: -4, Width: 4, Height: 4}, trap.Frames[0].Hitbox)
+	assert.Equal(t, &Hitbox{Y: -10, Radius: 8}, trap.Frames[1].Hitbox)
+	assert.Equal(t, &Hitbox{Y: -10, Radius: 8}, trap.Frames[2].Hitbox)
+}
+
+func TestLoadInvalidAnimations(t *testing.T) {
+	lookup, _ := testImages("a", "b")
+	testData := []struct {
+		name    string
+		content string
+	}{
+		{"invalid JSON", `{"walk": {`},
+		{"unknown field", `{"walk": {"frames": ["a"], "duration": 4, "speed": 2}}`},
+		{"no frame", `{"walk": {"frames": [], "duration": 4}}`},
+		{"unknown image", `{"walk": {"frames": ["a", "z"], "duration": 4}}`},
+		{"no duration", `{"walk": {"frames": ["a", "b"]}}`},
+		{"wrong number of durations", `{"walk": {"frames": ["a", "b"], "durations": [4]}}`},
+		{"loop from outside", `{"walk": {"frames": ["a", "b"], "duration": 4, "loop": true, "loop_from": 2}}`},
+		{"wrong number of hitboxes", `{"walk": {"frames": ["a", "b"], "duration": 4, "hitboxes": [{"radius": 4}]}}`},
+		{"event outside", `{"walk": {"frames": ["a", "b"], "duration": 4, "events": [{"frame": 2, "name": "end"}]}}`},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			_, err := LoadAnimations(strings.NewReader(testItem.content), lookup)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// playTest returns the index of the image displayed after each update
+func playTest(sprite *Sprite, images []*ebiten.Image, updates int) []int {
+	indexes := make([]int, updates)
+	for i := range indexes {
+		sprite.Update()
+		indexes[i] = -1
+		for index, image := range images {
+			if sprite.image == image {
+				indexes[i] = index
+			}
+		}
+	}
+	return indexes
+}
+
+func TestSpritePlay(t *testing.T) {
+	_, images := testImages("a", "b", "c")
+	frames := []*ebiten.Image{images["a"], images["b"], images["c"]}
+
+	once := NewAnimation("once", frames, 2, false)
+	sprite := NewSprite(XLeft, YTop).Play(once)
+	assert.Equal(t, frames[0], sprite.image)
+	assert.Equal(t, []int{0, 1, 1, 2, 2, 2, 2}, playTest(sprite, frames, 7))
+	assert.True(t, sprite.IsFinished())
+
+	loop := &Animation{
+		Frames:   []Frame{{Image: frames[0], Duration: 1}, {Image: frames[1], Duration: 2}, {Image: frames[2], Duration: 1}},
+		Loop:     true,
+		LoopFrom: 1,
+	}
+	sprite.Play(loop)
+	assert.Equal(t, []int{1, 1, 2, 1, 1, 2, 1}, playTest(sprite, frames, 7))
+	assert.False(t, sprite.IsFinished())
+}
+
+func TestSpriteSwitch(t *testing.T) {
+	_, images := testImages("a", "b", "c")
+	frames := []*ebiten.Image{images["a"], images["b"], images["c"]}
+	walk := NewAnimation("walk", frames, 1, true)
+	still := NewAnimation("still", frames[2:], 1, true)
+
+	sprite := NewSprite(XLeft, YTop).Switch(walk)
+	assert.Equal(t, []int{1}, playTest(sprite, frames, 1))
+	// same animation: carries on
+	sprite.Switch(walk)
+	assert.Equal(t, []int{2}, playTest(sprite, frames, 1))
+	// another animation: starts from the beginning
+	sprite.Switch(still)
+	assert.Equal(t, still, sprite.Playing())
+	assert.Equal(t, frames[2], sprite.image)
+	// back to the first one: restarts it too
+	sprite.Switch(walk)
+	assert.Equal(t, frames[0], sprite.image)
+}
+
+func TestSpriteEvents(t *testing.T) {
+	_, images := testImages("a", "b", "c")
+	fire := &Animation{
+		Frames: []Frame{{Image: images["a"], Duration: 2}, {Image: images["b"], Duration: 2}, {Image: images["c"], Duration: 2}},
+		Events: []AnimationEvent{{Frame: 0, Name: "aim"}, {Frame: 2, Name: "fire"}, {Frame: 2, Name: "sound"}},
+	}
+	sprite := NewSprite(XLeft, YTop).Play(fire)
+	assert.Equal(t, []string{"aim"}, sprite.Events())
+	assert.Nil(t, sprite.Events())
+
+	received := make([][]string, 0)
+	for i := 0; i < 7; i++ {
+		sprite.Update()
+		received = append(received, sprite.Events())
+	}
+	assert.Equal(t, [][]string{nil, nil, nil, {"fire", "sound"}, nil, {EventDone}, nil}, received)
+}
+
+func TestSpriteEventsWhenLooping(t *testing.T) {
+	_, images := testImages("a", "b")
+	loop := &Animation{
+		Frames:   []Frame{{Image: images["a"], Duration: 1}, {Image: images["b"], Duration: 1}},
+		Loop:     true,
+		LoopFrom: 1,
+		Events:   []AnimationEvent{{Frame: 0, Name: "start"}, {Frame: 1, Name: "step"}},
+	}
+	sprite := NewSprite(XLeft, YTop).Play(loop)
+	for i := 0; i < 3; i++ {
+		sprite.Update()
+	}
+	// the first frame is not played again when looping
+	assert.Equal(t, []string{"start", "step", "step", "step"}, sprite.Events())
+}
+
+func TestSpriteEventsOnce(t *testing.T) {
+	_, images := testImages("a", "b")
+	loop := &Animation{
+		Frames: []Frame{{Image: images["a"], Duration: 1}, {Image: images["b"], Duration: 1}},
+		Loop:   true,
+		Events: []AnimationEvent{{Frame: 0, Name: "sound", Once: true}, {Frame: 0, Name: "start"}},
+	}
+	sprite := NewSprite(XLeft, YTop).Play(loop)
+	for i := 0; i < 4; i++ {
+		sprite.Update()
+	}
+	assert.Equal(t, []string{"sound", "start", "start", "start"}, sprite.Events())
+
+	// playing the animation again sends the event again
+	sprite.Play(loop)
+	assert.Equal(t, []string{"sound", "start"}, sprite.Events())
+}
@@ -0,0 +1,182 @@
+package lib
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Region is the position of an image packed in an atlas
+type Region struct {
+	Name   string
+	Page   int             // index of the atlas page
+	Bounds image.Rectangle // position in the page, without the padding
+}
+
+// Atlas packs many small images into a few large ones (the pages).
+// The packed images are sub-images of the pages: drawing them one after the other
+// doesn't break the batching of draw calls.
+type Atlas struct {
+	pages   []*ebiten.Image
+	regions []Region
+	images  map[string]*ebiten.Image
+}
+
+// NewAtlas packs the images into pages no larger than pageSize x pageSize.
+// Each image is surrounded by a transparent border of padding pixels, to avoid bleeding from its neighbours.
+func NewAtlas(images map[string]image.Image, pageSize, padding int) (*Atlas, error) {
+	names := make([]string, 0, len(images))
+	sizes := make(map[string]image.Point, len(images))
+	for name, img := range images {
+		names = append(names, name)
+		sizes[name] = img.Bounds().Size()
+	}
+	regions, pageSizes, err := pack(names, sizes, pageSize, padding)
+	if err != nil {
+		return nil, err
+	}
+
+	// draw all the images into pages in memory, then send each page to the GPU once
+	pixels := make([]*image.RGBA, len(pageSizes))
+	for i, size := range pageSizes {
+		pixels[i] = image.NewRGBA(image.Rectangle{Max: size})
+	}
+	for _, region := range regions {
+		img := images[region.Name]
+		draw.Draw(pixels[region.Page], region.Bounds, img, img.Bounds().Min, draw.Src)
+	}
+	a := &Atlas{
+		pages:   make([]*ebiten.Image, len(pixels)),
+		regions: regions,
+		images:  make(map[string]*ebiten.Image, len(regions)),
+	}
+	for i, page := range pixels {
+		a.pages[i] = ebiten.NewImageFromImage(page)
+	}
+	for _, region := range regions {
+		a.images[region.Name] = a.pages[region.Page].SubImage(region.Bounds).(*ebiten.Image)
+	}
+	return a, nil
+}
+
+// Image returns the packed image, or nil if no image was packed under that name
+func (a *Atlas) Image(name string) *ebiten.Image {
+	return a.images[name]
+}
+
+// Images returns all the packed images by name
+func (a *Atlas) Images() map[string]*ebiten.Image {
+	return a.images
+}
+
+// Replace the pixels of a packed image. The sub-image keeps the same address, so everything
+// drawing it shows the new pixels. The new image must have the same size as the packed image.
+func (a *Atlas) Replace(name string, img image.Image) error {
+	packed := a.images[name]
+	if packed == nil {
+		return fmt.Errorf("image %q is not in the atlas", name)
+	}
+	size := img.Bounds().Size()
+	if size != packed.Bounds().Size() {
+		return fmt.Errorf("image %q changed size from %v to %v", name, packed.Bounds().Size(), size)
+	}
+	pixels := image.NewRGBA(image.Rectangle{Max: size})
+	draw.Draw(pixels, pixels.Bounds(), img, img.Bounds().Min, draw.Src)
+	packed.WritePixels(pixels.Pix)
+	return nil
+}
+
+// Pages returns the atlas pages
+func (a *Atlas) Pages() []*ebiten.Image {
+	return a.pages
+}
+
+// Regions returns the position of all the packed images, sorted by page and position
+func (a *Atlas) Regions() []Region {
+	return a.regions
+}
+
+// shelf is a row of images in an atlas page
+type shelf struct {
+	page   int
+	y      int
+	height int
+	x      int // next free position on the shelf
+}
+
+// pack places the images on shelves, the tallest images first.
+// It returns the position of each image and the size of each page, cropped to the space used.
+func pack(names []string, sizes map[string]image.Point, pageSize, padding int) ([]Region, []image.Point, error) {
+	// sort by height, then width, then name: the same images are always packed the same way
+	sort.Slice(names, func(i, j int) bool {
+		a, b := sizes[names[i]], sizes[names[j]]
+		if a.Y != b.Y {
+			return a.Y > b.Y
+		}
+		if a.X != b.X {
+			return a.X > b.X
+		}
+		return names[i] < names[j]
+	})
+
+	regions := make([]Region, 0, len(names))
+	shelves := make([]*shelf, 0, 16)
+	pageSizes := make([]image.Point, 0, 2)
+	pageBottom := 0 // next free position on the last page
+	for _, name := range names {
+		size := sizes[name]
+		width, height := size.X+2*padding, size.Y+2*padding
+		if width > pageSize || height > pageSize {
+			return nil, nil, fmt.Errorf("image %q (%dx%d) does not fit in an atlas page of %dx%d", name, size.X, size.Y, pageSize, pageSize)
+		}
+		var found *shelf
+		for _, s := range shelves {
+			if height <= s.height && s.x+width <= pageSize {
+				found = s
+				break
+			}
+		}
+		if found == nil {
+			if len(pageSizes) == 0 || pageBottom+height > pageSize {
+				pageSizes = append(pageSizes, image.Point{})
+				pageBottom = 0
+			}
+			found = &shelf{
+				page:   len(pageSizes) - 1,
+				y:      pageBottom,
+				height: height,
+			}
+			shelves = append(shelves, found)
+			pageBottom += height
+		}
+		topLeft := image.Pt(found.x+padding, found.y+padding)
+		regions = append(regions, Region{
+			Name:   name,
+			Page:   found.page,
+			Bounds: image.Rectangle{Min: topLeft, Max: topLeft.Add(size)},
+		})
+		found.x += width
+
+		used := &pageSizes[found.page]
+		if found.x > used.X {
+			used.X = found.x
+		}
+		if found.y+found.height > used.Y {
+			used.Y = found.y + found.height
+		}
+	}
+	sort.SliceStable(regions, func(i, j int) bool {
+		a, b := regions[i], regions[j]
+		if a.Page != b.Page {
+			return a.Page < b.Page
+		}
+		if a.Bounds.Min.Y != b.Bounds.Min.Y {
+			return a.Bounds.Min.Y < b.Bounds.Min.Y
+		}
+		return a.Bounds.Min.X < b.Bounds.Min.X
+	})
+	return regions, pageSizes, nil
+}
@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func packTest(t *testing.T, sizes map[string]image.Point, pageSize, padding int) ([]Region, []image.Point) {
+	t.Helper()
+	names := make([]string, 0, len(sizes))
+	for name := range sizes {
+		names = append(names, name)
+	}
+	regions, pages, err := pack(names, sizes, pageSize, padding)
+	require.NoError(t, err)
+	require.Len(t, regions, len(sizes))
+	return regions, pages
+}
+
+func TestPackShelves(t *testing.T) {
+	regions, pages := packTest(t, map[string]image.Point{
+		"tall":   {10, 20},
+		"small1": {10, 10},
+		"small2": {10, 10},
+		"wide":   {30, 10},
+	}, 40, 0)
+
+	assert.Equal(t, []image.Point{{40, 30}}, pages)
+	assert.Equal(t, []Region{
+		{"tall", 0, image.Rect(0, 0, 10, 20)},
+		{"wide", 0, image.Rect(10, 0, 40, 10)},
+		{"small1", 0, image.Rect(0, 20, 10, 30)},
+		{"small2", 0, image.Rect(10, 20, 20, 30)},
+	}, regions)
+}
+
+func TestPackPadding(t *testing.T) {
+	regions, pages := packTest(t, map[string]image.Point{
+		"a": {10, 10},
+		"b": {10, 10},
+	}, 100, 1)
+
+	assert.Equal(t, []image.Point{{24, 12}}, pages)
+	assert.Equal(t, image.Rect(1, 1, 11, 11), regions[0].Bounds)
+	assert.Equal(t, image.Rect(13, 1, 23, 11), regions[1].Bounds)
+}
+
+func TestPackPages(t *testing.T) {
+	sizes := make(map[string]image.Point, 10)
+	for i := 0; i < 10; i++ {
+		sizes[string(rune('a'+i))] = image.Pt(16, 16)
+	}
+	regions, pages := packTest(t, sizes, 32, 0)
+
+	// 4 images per page
+	assert.Equal(t, []image.Point{{32, 32}, {32, 32}, {32, 16}}, pages)
+	for i, region := range regions {
+		assert.Equal(t, i/4, region.Page)
+	}
+	// no overlap on the same page
+	for i, a := range regions {
+		for _, b := range regions[i+1:] {
+			if a.Page == b.Page {
+				assert.False(t, a.Bounds.Overlaps(b.Bounds), "%s overlaps %s", a.Name, b.Name)
+			}
+		}
+	}
+}
+
+func TestPackTooLarge(t *testing.T) {
+	_, _, err := pack([]string{"huge"}, map[string]image.Point{"huge": {100, 10}}, 64, 0)
+	assert.Error(t, err)
+}
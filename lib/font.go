@@ -0,0 +1,237 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Glyph is the image of a character in a bitmap font
+type Glyph struct {
+	Image   *ebiten.Image
+	Bounds  image.Rectangle // position of the glyph in the sheet
+	Advance int             // horizontal distance to the next character
+}
+
+// BitmapFont draws text using glyphs cut from a single image (the glyph sheet).
+//
+// The metrics file describes the glyphs in the sheet, one instruction per line:
+//
+//	height <line height>
+//	glyph <code point> <x> <y> <width> <height> <advance>
+//	kern <code point> <code point> <offset>
+//
+// Empty lines and lines starting with # are ignored. The text is drawn from the top of the line:
+// the ascent instruction of a SheetFace is not used, and is refused.
+type BitmapFont struct {
+	glyphs   map[rune]*Glyph
+	kerning  map[[2]rune]int
+	height   int
+	fallback rune
+	op       *ebiten.DrawImageOptions
+}
+
+// LoadBitmapFont creates a new font from a glyph sheet and its metrics file.
+// If sheet is nil, the font can only be used to measure text.
+func LoadBitmapFont(sheet *ebiten.Image, metrics io.Reader) (*BitmapFont, error) {
+	parsed, err := parseFontMetrics(metrics)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.ascent != 0 {
+		return nil, fmt.Errorf("ascent is only used by a SheetFace: a BitmapFont draws from the top of the line")
+	}
+	f := &BitmapFont{
+		glyphs:   make(map[rune]*Glyph, len(parsed.glyphs)),
+		kerning:  parsed.kerning,
+		height:   parsed.height,
+		fallback: '?',
+		op:       &ebiten.DrawImageOptions{},
+	}
+	for char, glyph := range parsed.glyphs {
+		glyph := glyph
+		if sheet != nil {
+			glyph.Image = sheet.SubImage(glyph.Bounds).(*ebiten.Image)
+		}
+		f.glyphs[char] = &glyph
+	}
+	return f, nil
+}
+
+// fontMetrics is the content of a metrics file, without the images of the glyphs
+type fontMetrics struct {
+	height  int
+	ascent  int // 0 when not in the file
+	glyphs  map[rune]Glyph
+	kerning map[[2]rune]int
+}
+
+// parseFontMetrics reads a metrics file (see BitmapFont for the format)
+func parseFontMetrics(metrics io.Reader) (*fontMetrics, error) {
+	f := &fontMetrics{
+		glyphs:  make(map[rune]Glyph, 96),
+		kerning: make(map[[2]rune]int),
+	}
+	scanner := bufio.NewScanner(metrics)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		values := make([]int, len(fields)-1)
+		for i, field := range fields[1:] {
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			values[i] = value
+		}
+		switch {
+		case fields[0] == "height" && len(values) == 1:
+			f.height = values[0]
+		case fields[0] == "ascent" && len(values) == 1:
+			f.ascent = values[0]
+		case fields[0] == "glyph" && len(values) == 6:
+			f.glyphs[rune(values[0])] = Glyph{
+				Bounds:  image.Rect(values[1], values[2], values[1]+values[3], values[2]+values[4]),
+				Advance: values[5],
+			}
+		case fields[0] == "kern" && len(values) == 3:
+			f.kerning[[2]rune{rune(values[0]), rune(values[1])}] = values[2]
+		default:
+			return nil, fmt.Errorf("line %d: invalid instruction %q", lineNum, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if f.height == 0 {
+		return nil, fmt.Errorf("missing font height")
+	}
+	return f, nil
+}
+
+// SetFallback sets the character displayed in place of characters missing from the font
+func (f *BitmapFont) SetFallback(char rune) *BitmapFont {
+	f.fallback = char
+	return f
+}
+
+// Height returns the height of a line of text
+func (f *BitmapFont) Height() int {
+	return f.height
+}
+
+// Width returns the width of a single line of text in pixels
+func (f *BitmapFont) Width(text string) int {
+	width := 0
+	previous := rune(0)
+	for _, char := range text {
+		glyph := f.glyph(char)
+		if glyph == nil {
+			continue
+		}
+		width += f.kerning[[2]rune{previous, char}] + glyph.Advance
+		previous = char
+	}
+	return width
+}
+
+// Draw a single line of text. The x coordinate is the left, centre or right of the text, depending on align.
+// The text is tinted with the colour, unless it's nil.
+func (f *BitmapFont) Draw(screen *ebiten.Image, text string, x, y float64, align XType, clr color.Color) {
+	switch align {
+	case XCentre:
+		x -= float64(f.Width(text)) / 2
+	case XRight:
+		x -= float64(f.Width(text))
+	}
+	f.op.ColorScale.Reset()
+	if clr != nil {
+		f.op.ColorScale.ScaleWithColor(clr)
+	}
+	previous := rune(0)
+	for _, char := range text {
+		glyph := f.glyph(char)
+		if glyph == nil {
+			continue
+		}
+		x += float64(f.kerning[[2]rune{previous, char}])
+		if glyph.Image != nil {
+			f.op.GeoM.Reset()
+			f.op.GeoM.Translate(x, y)
+			screen.DrawImage(glyph.Image, f.op)
+		}
+		x += float64(glyph.Advance)
+		previous = char
+	}
+}
+
+// DrawBox draws the text inside the box, wrapping words that don't fit on a line.
+// Each line is aligned to the left, centre or right of the box. Lines going past the bottom of the box are not drawn.
+func (f *BitmapFont) DrawBox(screen *ebiten.Image, text string, box image.Rectangle, align XType, clr color.Color) {
+	x := float64(box.Min.X)
+	switch align {
+	case XCentre:
+		x = float64(box.Min.X+box.Max.X) / 2
+	case XRight:
+		x = float64(box.Max.X)
+	}
+	y := box.Min.Y
+	for _, line := range f.Wrap(text, box.Dx()) {
+		if y+f.height > box.Max.Y {
+			return
+		}
+		f.Draw(screen, line, x, float64(y), align, clr)
+		y += f.height
+	}
+}
+
+// Wrap splits the text in lines no wider than width. A word wider than width is left on its own line.
+// Line breaks already in the text are kept.
+func (f *BitmapFont) Wrap(text string, width int) []string {
+	lines := make([]string, 0, 4)
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line == "" {
+				line = word
+				continue
+			}
+			if f.Width(line+" "+word) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (f *BitmapFont) glyph(char rune) *Glyph {
+	if glyph, found := f.glyphs[char]; found {
+		return glyph
+	}
+	return f.glyphs[f.fallback]
+}
+
+// Covers returns true when all the characters of the text are in the font (without using the fallback character)
+func (f *BitmapFont) Covers(text string) bool {
+	for _, char := range text {
+		if _, found := f.glyphs[char]; !found {
+			return false
+		}
+	}
+	return true
+}
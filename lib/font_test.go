@@ -0,0 +1,104 @@
+package lib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMetrics = `
+# test font: every glyph is 10 pixels wide, except the space
+height 20
+glyph 32 0 0 1 1 5
+glyph 63 10 0 10 20 10
+glyph 65 20 0 10 20 10
+glyph 66 30 0 10 20 10
+glyph 86 40 0 10 20 10
+kern 65 86 -2
+`
+
+func loadTestFont(t *testing.T) *BitmapFont {
+	t.Helper()
+	font, err := LoadBitmapFont(nil, strings.NewReader(testMetrics))
+	require.NoError(t, err)
+	return font
+}
+
+func TestFontWidth(t *testing.T) {
+	font := loadTestFont(t)
+	assert.Equal(t, 20, font.Height())
+
+	testData := []struct {
+		text  string
+		width int
+	}{
+		{"", 0},
+		{"A", 10},
+		{"AB", 20},
+		{"A B", 25},
+		{"AV", 18},   // kerning
+		{"VA", 20},   // kerning only applies in one direction
+		{"AZ", 20},   // missing character is replaced by "?"
+		{"A\tB", 30}, // so are control characters
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.text, func(t *testing.T) {
+			assert.Equal(t, testItem.width, font.Width(testItem.text))
+		})
+	}
+}
+
+func TestFontNoFallback(t *testing.T) {
+	font := loadTestFont(t).SetFallback(0)
+	assert.Equal(t, 10, font.Width("AZ"))
+}
+
+func TestFontCovers(t *testing.T) {
+	font := loadTestFont(t)
+	assert.True(t, font.Covers(""))
+	assert.True(t, font.Covers("AB BA"))
+	assert.False(t, font.Covers("ABZ"))
+	assert.False(t, font.Covers("ÀB"))
+}
+
+func TestFontWrap(t *testing.T) {
+	font := loadTestFont(t)
+
+	testData := []struct {
+		text  string
+		width int
+		lines []string
+	}{
+		{"AB AB AB", 100, []string{"AB AB AB"}},
+		{"AB AB AB", 45, []string{"AB AB", "AB"}},
+		{"AB AB AB", 44, []string{"AB", "AB", "AB"}},
+		{"ABABAB AB", 30, []string{"ABABAB", "AB"}},
+		{"AB  AB", 100, []string{"AB AB"}},
+		{"AB\nAB", 100, []string{"AB", "AB"}},
+		{"", 100, []string{""}},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.text, func(t *testing.T) {
+			assert.Equal(t, testItem.lines, font.Wrap(testItem.text, testItem.width))
+		})
+	}
+}
+
+func TestFontInvalidMetrics(t *testing.T) {
+	testData := []string{
+		"height",
+		"height twenty",
+		"glyph 65 0 0 10 20",
+		"width 10",
+		"glyph 65 0 0 10 20 10",
+		"height 20\nascent 16",
+	}
+	for _, metrics := range testData {
+		t.Run(metrics, func(t *testing.T) {
+			_, err := LoadBitmapFont(nil, strings.NewReader(metrics))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package lib
+
+import "math"
+
+// TeleportDistance is the distance (in pixels, on any axis) above which a sprite moving in one tick
+// is drawn at its new position straight away, instead of sliding across the screen
+const TeleportDistance = 100.0
+
+// interpolation of the sprite positions between two ticks, shared by all the sprites
+var interpolation struct {
+	enabled  bool
+	tick     uint64  // current tick
+	progress float64 // from 0 (start of the tick) to 1 (start of the next tick)
+}
+
+// SetInterpolation draws the sprites between their position at the previous tick and their current position.
+// It makes the movements smooth when the screen refreshes faster than the ticks.
+func SetInterpolation(enabled bool) {
+	interpolation.enabled = enabled
+}
+
+// IsInterpolation returns true when the sprite positions are interpolated
+func IsInterpolation() bool {
+	return interpolation.enabled
+}
+
+// NextTick starts a new tick. It should be called at the start of the game update, before moving any sprite.
+func NextTick() {
+	interpolation.tick++
+	interpolation.progress = 0
+}
+
+// SetTickProgress sets how far the frame about to be drawn is between the last tick (0) and the next one (1)
+func SetTickProgress(progress float64) {
+	interpolation.progress = math.Max(0, math.Min(1, progress))
+}
+
+// setPosition moves the sprite, keeping its position from before the current tick
+func (s *Sprite) setPosition(x, y float64) {
+	if !s.positioned {
+		// nowhere to come from
+		s.previousX, s.previousY = x, y
+		s.positioned = true
+	} else if s.movedTick != interpolation.tick {
+		s.previousX, s.previousY = s.x, s.y
+	}
+	s.movedTick = interpolation.tick
+	s.x, s.y = x, y
+}
+
+// Teleport moves the sprite without sliding from its previous position, like a sprite taken again from a pool
+func (s *Sprite) Teleport(x, y float64) *Sprite {
+	s.setPosition(x, y)
+	s.previousX, s.previousY = x, y
+	return s
+}
+
+// drawPosition returns the position where the sprite is drawn
+func (s *Sprite) drawPosition() (float64, float64) {
+	if !interpolation.enabled || s.movedTick != interpolation.tick {
+		return s.x, s.y
+	}
+	distanceX, distanceY := s.x-s.previousX, s.y-s.previousY
+	if math.Abs(distanceX) > TeleportDistance || math.Abs(distanceY) > TeleportDistance {
+		return s.x, s.y
+	}
+	return s.previousX + distanceX*interpolation.progress, s.previousY + distanceY*interpolation.progress
+}
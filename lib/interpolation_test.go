@@ -0,0 +1,90 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// drawnAt returns the position of the top left corner of the sprite image on the screen
+func drawnAt(sprite *Sprite) [2]float64 {
+	geoM := sprite.geoM(10, 10)
+	x, y := geoM.Apply(0, 0)
+	return [2]float64{x, y}
+}
+
+func TestInterpolation(t *testing.T) {
+	SetInterpolation(true)
+	defer SetInterpolation(false)
+
+	testData := []struct {
+		name     string
+		move     func(sprite *Sprite) // on the tick after the sprite was placed at 100, 100
+		progress float64
+		expected [2]float64
+	}{
+		{"start of the tick", func(s *Sprite) { s.Move(10, -4) }, 0, [2]float64{100, 100}},
+		{"middle of the tick", func(s *Sprite) { s.Move(10, -4) }, 0.5, [2]float64{105, 98}},
+		{"end of the tick", func(s *Sprite) { s.Move(10, -4) }, 1, [2]float64{110, 96}},
+		{"progress too far", func(s *Sprite) { s.Move(10, -4) }, 2, [2]float64{110, 96}},
+		{"moved twice in the tick", func(s *Sprite) { s.Move(10, 0).Move(10, 0) }, 0.5, [2]float64{110, 100}},
+		{"move to", func(s *Sprite) { s.MoveTo(120, 100) }, 0.25, [2]float64{105, 100}},
+		{"move to type", func(s *Sprite) { s.MoveToType(130, 110, XLeft, YTop) }, 0.5, [2]float64{115, 105}},
+		{"not moving", func(s *Sprite) {}, 0.5, [2]float64{100, 100}},
+		{"too far", func(s *Sprite) { s.MoveTo(100, 100+TeleportDistance+1) }, 0.5, [2]float64{100, 201}},
+		{"teleport", func(s *Sprite) { s.Teleport(150, 120) }, 0.5, [2]float64{150, 120}},
+		{"move after teleport", func(s *Sprite) { s.Teleport(150, 120).Move(10, 0) }, 0.5, [2]float64{155, 120}},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			sprite := NewSprite(XLeft, YTop).SetSize(10, 10)
+			NextTick()
+			sprite.MoveTo(100, 100)
+			NextTick()
+			testItem.move(sprite)
+			SetTickProgress(testItem.progress)
+			assert.Equal(t, testItem.expected, drawnAt(sprite))
+		})
+	}
+}
+
+func TestInterpolationStoppedMoving(t *testing.T) {
+	SetInterpolation(true)
+	defer SetInterpolation(false)
+
+	sprite := NewSprite(XLeft, YTop).SetSize(10, 10)
+	NextTick()
+	sprite.MoveTo(100, 100)
+	NextTick()
+	sprite.MoveTo(110, 100)
+	// the sprite didn't move during the next tick
+	NextTick()
+	SetTickProgress(0.5)
+	assert.Equal(t, [2]float64{110, 100}, drawnAt(sprite))
+}
+
+func TestInterpolationFirstPosition(t *testing.T) {
+	SetInterpolation(true)
+	defer SetInterpolation(false)
+
+	// a new sprite doesn't come from 0, 0
+	sprite := NewSprite(XLeft, YTop).SetSize(10, 10)
+	NextTick()
+	sprite.MoveTo(50, 50)
+	SetTickProgress(0.5)
+	assert.Equal(t, [2]float64{50, 50}, drawnAt(sprite))
+}
+
+func TestInterpolationDisabled(t *testing.T) {
+	SetInterpolation(false)
+
+	sprite := NewSprite(XLeft, YTop).SetSize(10, 10)
+	NextTick()
+	sprite.MoveTo(100, 100)
+	NextTick()
+	sprite.Move(10, 0)
+	SetTickProgress(0.5)
+	assert.Equal(t, [2]float64{110, 100}, drawnAt(sprite))
+	// the game logic always uses the current position
+	assert.Equal(t, 110.0, sprite.X(XLeft))
+}
@@ -0,0 +1,171 @@
+package lib
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Drawable is anything that can be drawn on a layer
+type Drawable interface {
+	Draw(screen *ebiten.Image)
+}
+
+// DrawFunc is a function used as a Drawable
+type DrawFunc func(screen *ebiten.Image)
+
+// Draw calls the function
+func (f DrawFunc) Draw(screen *ebiten.Image) {
+	f(screen)
+}
+
+// LayerEffect draws the content of a layer (src) onto the image below (dst), like with an offset or a shader
+type LayerEffect func(dst, src *ebiten.Image)
+
+type layerItem struct {
+	drawable Drawable
+	z        int
+}
+
+// Layer draws its items in order of z value, then in the order they were added
+type Layer struct {
+	name   string
+	items  []layerItem
+	sorted bool
+	hidden bool
+	effect LayerEffect
+	buffer *ebiten.Image // the layer is drawn here first when it has an effect
+}
+
+// NewLayer creates an empty layer
+func NewLayer(name string) *Layer {
+	return &Layer{
+		name:   name,
+		items:  make([]layerItem, 0, 10),
+		sorted: true,
+	}
+}
+
+// Name of the layer
+func (l *Layer) Name() string {
+	return l.name
+}
+
+// Add a drawable with its z value: the items with a higher z value are drawn on top
+func (l *Layer) Add(drawable Drawable, z int) *Layer {
+	l.items = append(l.items, layerItem{drawable: drawable, z: z})
+	l.sorted = false
+	return l
+}
+
+// Remove a drawable from the layer. It returns false if the drawable was not found
+func (l *Layer) Remove(drawable Drawable) bool {
+	for i, item := range l.items {
+		if item.drawable == drawable {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes all the drawables
+func (l *Layer) Clear() *Layer {
+	l.items = l.items[:0]
+	l.sorted = true
+	return l
+}
+
+// Len returns the number of drawables in the layer
+func (l *Layer) Len() int {
+	return len(l.items)
+}
+
+// SetVisible shows or hides the layer
+func (l *Layer) SetVisible(visible bool) *Layer {
+	l.hidden = !visible
+	return l
+}
+
+// IsVisible returns true when the layer is drawn
+func (l *Layer) IsVisible() bool {
+	return !l.hidden
+}
+
+// SetEffect draws the layer on an offscreen image, then onto the screen through the effect.
+// A nil effect draws the layer straight onto the screen.
+func (l *Layer) SetEffect(effect LayerEffect) *Layer {
+	l.effect = effect
+	return l
+}
+
+// Draw all the items of the layer
+func (l *Layer) Draw(screen *ebiten.Image) {
+	if l.hidden {
+		return
+	}
+	if !l.sorted {
+		sort.SliceStable(l.items, func(i, j int) bool {
+			return l.items[i].z < l.items[j].z
+		})
+		l.sorted = true
+	}
+	target := screen
+	if l.effect != nil {
+		bounds := screen.Bounds()
+		if l.buffer == nil || l.buffer.Bounds().Size() != bounds.Size() {
+			l.buffer = ebiten.NewImage(bounds.Dx(), bounds.Dy())
+		}
+		l.buffer.Clear()
+		target = l.buffer
+	}
+	for _, item := range l.items {
+		item.drawable.Draw(target)
+	}
+	if l.effect != nil {
+		l.effect(screen, l.buffer)
+	}
+}
+
+// Layers is a stack of named layers, drawn from the first to the last
+type Layers struct {
+	layers []*Layer
+}
+
+// NewLayers creates the layers, from the bottom one to the top one
+func NewLayers(names ...string) *Layers {
+	layers := make([]*Layer, len(names))
+	for i, name := range names {
+		layers[i] = NewLayer(name)
+	}
+	return &Layers{
+		layers: layers,
+	}
+}
+
+// Layer returns the layer by name, or nil if not found
+func (l *Layers) Layer(name string) *Layer {
+	for _, layer := range l.layers {
+		if layer.name == name {
+			return layer
+		}
+	}
+	return nil
+}
+
+// Add a drawable to the named layer. The layer must exist.
+func (l *Layers) Add(name string, drawable Drawable, z int) {
+	layer := l.Layer(name)
+	if layer == nil {
+		panic(fmt.Sprintf("unknown layer %q", name))
+	}
+	layer.Add(drawable, z)
+}
+
+// Draw all the visible layers
+func (l *Layers) Draw(screen *ebiten.Image) {
+	for _, layer := range l.layers {
+		layer.Draw(screen)
+	}
+}
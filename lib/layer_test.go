@@ -0,0 +1,89 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder returns a drawable adding its name to the list when drawn
+func recorder(drawn *[]string, name string) DrawFunc {
+	return func(screen *ebiten.Image) {
+		*drawn = append(*drawn, name)
+	}
+}
+
+func TestLayerOrder(t *testing.T) {
+	testData := []struct {
+		name     string
+		items    []string
+		z        []int
+		expected []string
+	}{
+		{"no item", []string{}, []int{}, []string{}},
+		{"same z keeps the order", []string{"a", "b", "c"}, []int{0, 0, 0}, []string{"a", "b", "c"}},
+		{"by z", []string{"a", "b", "c"}, []int{30, 10, 20}, []string{"b", "c", "a"}},
+		{"negative z", []string{"a", "b", "c", "d"}, []int{0, -1, 0, 5}, []string{"b", "a", "c", "d"}},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			drawn := make([]string, 0)
+			layer := NewLayer("test")
+			for i, name := range testItem.items {
+				layer.Add(recorder(&drawn, name), testItem.z[i])
+			}
+			layer.Draw(nil)
+			assert.Equal(t, testItem.expected, drawn)
+			assert.Equal(t, len(testItem.items), layer.Len())
+		})
+	}
+}
+
+func TestLayerAddAfterDraw(t *testing.T) {
+	drawn := make([]string, 0)
+	layer := NewLayer("test")
+	layer.Add(recorder(&drawn, "top"), 10)
+	layer.Draw(nil)
+	layer.Add(recorder(&drawn, "bottom"), 0)
+	drawn = drawn[:0]
+	layer.Draw(nil)
+	assert.Equal(t, []string{"bottom", "top"}, drawn)
+}
+
+func TestLayerRemoveAndClear(t *testing.T) {
+	drawn := make([]string, 0)
+	drawables := []*Sprite{NewSprite(XLeft, YTop), NewSprite(XLeft, YTop)}
+	layer := NewLayer("test")
+	layer.Add(drawables[0], 0).Add(drawables[1], 0).Add(recorder(&drawn, "func"), 0)
+	assert.True(t, layer.Remove(drawables[0]))
+	assert.False(t, layer.Remove(drawables[0]))
+	assert.Equal(t, 2, layer.Len())
+	layer.Clear()
+	assert.Equal(t, 0, layer.Len())
+	layer.Draw(nil)
+	assert.Empty(t, drawn)
+}
+
+func TestLayers(t *testing.T) {
+	drawn := make([]string, 0)
+	layers := NewLayers("world", "hud", "overlay")
+	layers.Add("overlay", recorder(&drawn, "title"), 0)
+	layers.Add("hud", recorder(&drawn, "score"), 0)
+	layers.Add("world", recorder(&drawn, "player"), 10)
+	layers.Add("world", recorder(&drawn, "level"), 0)
+
+	layers.Draw(nil)
+	assert.Equal(t, []string{"level", "player", "score", "title"}, drawn)
+
+	drawn = drawn[:0]
+	layers.Layer("hud").SetVisible(false)
+	assert.False(t, layers.Layer("hud").IsVisible())
+	layers.Draw(nil)
+	assert.Equal(t, []string{"level", "player", "title"}, drawn)
+
+	assert.Nil(t, layers.Layer("unknown"))
+	assert.Panics(t, func() {
+		layers.Add("unknown", recorder(&drawn, "nothing"), 0)
+	})
+}
@@ -0,0 +1,231 @@
+package lib
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ParticleEffect describes the particles released by a burst or an emitter.
+// Speeds are in pixels per tick, angles in radians (0 goes right, -π/2 goes up) and times in ticks.
+type ParticleEffect struct {
+	Count          int            // particles released by a burst
+	Rate           float64        // particles released per tick by an emitter
+	Radius         float64        // the particles appear at a random distance from the source, up to the radius
+	Lifetime       int            // how long a particle lives
+	LifetimeSpread int            // random number of ticks added to the lifetime, up to this value
+	Speed          float64        // initial speed
+	SpeedSpread    float64        // the initial speed varies randomly by up to this value either way
+	Angle          float64        // initial direction
+	AngleSpread    float64        // the initial direction varies randomly by up to this angle either way
+	Gravity        float64        // added to the vertical speed every tick
+	Drag           float64        // fraction of the speed lost every tick
+	Size           [2]float64     // size of the particle (in pixels) at the start and at the end of its life
+	Colour         [2]color.NRGBA // colour of the particle, alpha included, at the start and at the end of its life
+}
+
+type particle struct {
+	effect    *ParticleEffect
+	x, y      float64
+	previousX float64
+	previousY float64
+	speedX    float64
+	speedY    float64
+	age       int
+	lifetime  int
+}
+
+// Emitter releases particles continuously, at the rate of its effect, until it's stopped
+type Emitter struct {
+	effect  *ParticleEffect
+	x, y    float64
+	active  bool
+	pending float64 // fraction of a particle carried over to the next tick
+}
+
+// MoveTo moves the source of the particles
+func (e *Emitter) MoveTo(x, y float64) *Emitter {
+	e.x, e.y = x, y
+	return e
+}
+
+// Start releasing particles
+func (e *Emitter) Start() *Emitter {
+	e.active = true
+	return e
+}
+
+// Stop releasing particles: the particles already released live until the end of their lifetime
+func (e *Emitter) Stop() {
+	e.active = false
+	e.pending = 0
+}
+
+// IsActive returns true when the emitter is releasing particles
+func (e *Emitter) IsActive() bool {
+	return e.active
+}
+
+// ParticleSystem moves and draws a fixed number of particles, allocated once.
+// When all the particles are in use, the new ones are dropped.
+type ParticleSystem struct {
+	particles []particle // the live particles are at the start of the slice
+	live      int
+	emitters  []*Emitter
+	tick      uint64 // tick of the last update, to interpolate the positions
+	image     *ebiten.Image
+	op        ebiten.DrawImageOptions
+}
+
+// NewParticleSystem creates a particle system with room for capacity particles
+func NewParticleSystem(capacity int) *ParticleSystem {
+	// the particles are drawn with the centre pixel of a white image, to avoid blending with the transparent edges
+	white := ebiten.NewImage(3, 3)
+	white.Fill(color.White)
+	return &ParticleSystem{
+		particles: make([]particle, capacity),
+		image:     white.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image),
+	}
+}
+
+// NewEmitter creates an emitter of the effect, stopped. It should be created once, not on every tick.
+func (p *ParticleSystem) NewEmitter(effect *ParticleEffect) *Emitter {
+	emitter := &Emitter{effect: effect}
+	p.emitters = append(p.emitters, emitter)
+	return emitter
+}
+
+// Burst releases the particles of the effect all at once
+func (p *ParticleSystem) Burst(effect *ParticleEffect, x, y float64) {
+	for i := 0; i < effect.Count; i++ {
+		p.emit(effect, x, y)
+	}
+}
+
+// Count returns the number of live particles
+func (p *ParticleSystem) Count() int {
+	return p.live
+}
+
+// Clear removes all the particles and the emitters
+func (p *ParticleSystem) Clear() {
+	p.live = 0
+	clear(p.emitters)
+	p.emitters = p.emitters[:0]
+}
+
+// emit releases one particle, returning false when there's no room left
+func (p *ParticleSystem) emit(effect *ParticleEffect, x, y float64) bool {
+	if p.live >= len(p.particles) {
+		return false
+	}
+	distance := rand.Float64() * effect.Radius
+	direction := rand.Float64() * 2 * math.Pi
+	x += distance * math.Cos(direction)
+	y += distance * math.Sin(direction)
+
+	speed := effect.Speed + randomSpread(effect.SpeedSpread)
+	angle := effect.Angle + randomSpread(effect.AngleSpread)
+	lifetime := effect.Lifetime
+	if effect.LifetimeSpread > 0 {
+		lifetime += rand.Intn(effect.LifetimeSpread + 1)
+	}
+	p.particles[p.live] = particle{
+		effect:    effect,
+		x:         x,
+		y:         y,
+		previousX: x,
+		previousY: y,
+		speedX:    speed * math.Cos(angle),
+		speedY:    speed * math.Sin(angle),
+		lifetime:  max(lifetime, 1),
+	}
+	p.live++
+	return true
+}
+
+// Update releases the particles of the active emitters, then moves all the particles for one tick
+func (p *ParticleSystem) Update() {
+	p.tick = interpolation.tick
+	for _, emitter := range p.emitters {
+		if !emitter.active {
+			continue
+		}
+		emitter.pending += emitter.effect.Rate
+		for emitter.pending >= 1 {
+			p.emit(emitter.effect, emitter.x, emitter.y)
+			emitter.pending--
+		}
+	}
+	for i := 0; i < p.live; {
+		particle := &p.particles[i]
+		particle.age++
+		if particle.age >= particle.lifetime {
+			// the last live particle takes its place
+			p.live--
+			p.particles[i] = p.particles[p.live]
+			p.particles[p.live].effect = nil
+			continue
+		}
+		effect := particle.effect
+		particle.speedY += effect.Gravity
+		particle.speedX *= 1 - effect.Drag
+		particle.speedY *= 1 - effect.Drag
+		particle.previousX, particle.previousY = particle.x, particle.y
+		particle.x += particle.speedX
+		particle.y += particle.speedY
+		i++
+	}
+}
+
+// Draw the live particles
+func (p *ParticleSystem) Draw(screen *ebiten.Image) {
+	interpolate := interpolation.enabled && p.tick == interpolation.tick
+	for i := 0; i < p.live; i++ {
+		particle := &p.particles[i]
+		effect := particle.effect
+		life := float64(particle.age) / float64(particle.lifetime)
+		x, y := particle.x, particle.y
+		if interpolate {
+			x = lerp(particle.previousX, x, interpolation.progress)
+			y = lerp(particle.previousY, y, interpolation.progress)
+		}
+		size := lerp(effect.Size[0], effect.Size[1], life)
+		if size <= 0 {
+			continue
+		}
+		red, green, blue, alpha := lerpColour(effect.Colour[0], effect.Colour[1], life)
+
+		p.op.GeoM.Reset()
+		p.op.GeoM.Translate(-0.5, -0.5)
+		p.op.GeoM.Scale(size, size)
+		p.op.GeoM.Translate(x, y)
+		p.op.ColorScale.Reset()
+		// the colour scale is premultiplied by alpha
+		p.op.ColorScale.Scale(red*alpha, green*alpha, blue*alpha, alpha)
+		screen.DrawImage(p.image, &p.op)
+	}
+}
+
+// randomSpread returns a random value between -spread and spread
+func randomSpread(spread float64) float64 {
+	if spread == 0 {
+		return 0
+	}
+	return (rand.Float64()*2 - 1) * spread
+}
+
+func lerp(from, to, t float64) float64 {
+	return from + (to-from)*t
+}
+
+// lerpColour returns the colour components (between 0 and 1) at t between two colours
+func lerpColour(from, to color.NRGBA, t float64) (red, green, blue, alpha float32) {
+	component := func(from, to uint8) float32 {
+		return float32(lerp(float64(from), float64(to), t) / 0xff)
+	}
+	return component(from.R, to.R), component(from.G, to.G), component(from.B, to.B), component(from.A, to.A)
+}
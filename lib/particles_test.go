@@ -0,0 +1,147 @@
+package lib
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParticleBurst(t *testing.T) {
+	testData := []struct {
+		name     string
+		capacity int
+		count    int
+		bursts   int
+		expected int
+	}{
+		{"one burst", 100, 10, 1, 10},
+		{"two bursts", 100, 10, 2, 20},
+		{"full", 15, 10, 2, 15},
+		{"nothing", 100, 0, 3, 0},
+	}
+
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			particles := NewParticleSystem(testItem.capacity)
+			effect := &ParticleEffect{Count: testItem.count, Lifetime: 10}
+			for i := 0; i < testItem.bursts; i++ {
+				particles.Burst(effect, 100, 100)
+			}
+			assert.Equal(t, testItem.expected, particles.Count())
+		})
+	}
+}
+
+func TestParticleLifetime(t *testing.T) {
+	particles := NewParticleSystem(100)
+	particles.Burst(&ParticleEffect{Count: 5, Lifetime: 10}, 0, 0)
+	particles.Burst(&ParticleEffect{Count: 3, Lifetime: 20, LifetimeSpread: 5}, 0, 0)
+
+	for i := 0; i < 9; i++ {
+		particles.Update()
+	}
+	assert.Equal(t, 8, particles.Count())
+	particles.Update()
+	assert.Equal(t, 3, particles.Count())
+	for i := 0; i < 15; i++ {
+		particles.Update()
+	}
+	assert.Equal(t, 0, particles.Count())
+}
+
+func TestParticleMovement(t *testing.T) {
+	testData := []struct {
+		name     string
+		effect   ParticleEffect
+		ticks    int
+		expected [2]float64
+	}{
+		{"still", ParticleEffect{}, 10, [2]float64{100, 100}},
+		{"right", ParticleEffect{Speed: 2}, 10, [2]float64{120, 100}},
+		{"gravity", ParticleEffect{Gravity: 1}, 3, [2]float64{100, 106}},
+		{"drag", ParticleEffect{Speed: 4, Drag: 0.5}, 2, [2]float64{103, 100}},
+	}
+
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			effect := testItem.effect
+			effect.Count = 1
+			effect.Lifetime = 100
+			particles := NewParticleSystem(1)
+			particles.Burst(&effect, 100, 100)
+			for i := 0; i < testItem.ticks; i++ {
+				particles.Update()
+			}
+			position := [2]float64{particles.particles[0].x, particles.particles[0].y}
+			assert.InDeltaSlice(t, testItem.expected[:], position[:], 0.0001)
+		})
+	}
+}
+
+func TestParticleSpread(t *testing.T) {
+	effect := &ParticleEffect{Count: 100, Lifetime: 10, Radius: 5, Speed: 2, SpeedSpread: 1}
+	particles := NewParticleSystem(100)
+	particles.Burst(effect, 100, 100)
+	for _, particle := range particles.particles {
+		assert.InDelta(t, 100, particle.x, 5)
+		assert.InDelta(t, 100, particle.y, 5)
+		assert.InDelta(t, 2, particle.speedX, 1)
+		assert.InDelta(t, 0, particle.speedY, 0.0001)
+	}
+}
+
+func TestEmitter(t *testing.T) {
+	particles := NewParticleSystem(100)
+	emitter := particles.NewEmitter(&ParticleEffect{Rate: 0.5, Lifetime: 100})
+	assert.False(t, emitter.IsActive())
+
+	particles.Update()
+	assert.Equal(t, 0, particles.Count())
+
+	emitter.MoveTo(10, 10).Start()
+	for i := 0; i < 10; i++ {
+		particles.Update()
+	}
+	assert.Equal(t, 5, particles.Count())
+
+	emitter.Stop()
+	particles.Update()
+	assert.Equal(t, 5, particles.Count())
+
+	particles.Clear()
+	emitter.Start()
+	particles.Update()
+	particles.Update()
+	assert.Equal(t, 0, particles.Count())
+}
+
+func TestLerpColour(t *testing.T) {
+	from := color.NRGBA{0xff, 0x00, 0x80, 0xff}
+	to := color.NRGBA{0x00, 0xff, 0x80, 0x00}
+	testData := []struct {
+		t        float64
+		expected [4]float32
+	}{
+		{0, [4]float32{1, 0, 0x80 / 255.0, 1}},
+		{0.5, [4]float32{0.5, 0.5, 0x80 / 255.0, 0.5}},
+		{1, [4]float32{0, 1, 0x80 / 255.0, 0}},
+	}
+
+	for _, testItem := range testData {
+		red, green, blue, alpha := lerpColour(from, to, testItem.t)
+		assert.InDeltaSlice(t, testItem.expected[:], []float32{red, green, blue, alpha}, 0.0001)
+	}
+}
+
+func TestParticlesAllocationFree(t *testing.T) {
+	particles := NewParticleSystem(200)
+	burst := &ParticleEffect{Count: 20, Lifetime: 30, LifetimeSpread: 10, Speed: 2, AngleSpread: 3, Gravity: 0.1}
+	particles.NewEmitter(&ParticleEffect{Rate: 1.5, Lifetime: 20, Radius: 4}).Start()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		particles.Burst(burst, 100, 100)
+		particles.Update()
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,41 @@
+package lib
+
+import "fmt"
+
+// PlayMode represents the order the frames of an animation are played
+type PlayMode int
+
+// PlayMode
+const (
+	Forward  PlayMode = iota // from the first frame to the last one
+	Reverse                  // from the last frame to the first one
+	PingPong                 // from the first frame to the last one and back, without showing the last frame twice
+)
+
+// String representation of PlayMode
+func (m PlayMode) String() string {
+	switch m {
+	case Reverse:
+		return "reverse"
+	case PingPong:
+		return "ping-pong"
+	default:
+		return "forward"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (m PlayMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (m *PlayMode) UnmarshalText(text []byte) error {
+	for _, mode := range []PlayMode{Forward, Reverse, PingPong} {
+		if string(text) == mode.String() {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown play mode %q", text)
+}
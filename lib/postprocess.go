@@ -0,0 +1,94 @@
+package lib
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ShaderPass is a step of a post processing chain
+type ShaderPass struct {
+	Name     string
+	Shader   *ebiten.Shader
+	Uniforms map[string]any
+	Enabled  bool
+}
+
+// PostProcess draws an image through a chain of shaders.
+// The passes are drawn one after the other on two offscreen images, the last one straight onto the destination.
+type PostProcess struct {
+	passes  []*ShaderPass
+	buffers [2]*ebiten.Image
+	enabled []*ShaderPass // reused on each frame
+}
+
+// NewPostProcess creates an empty chain of shaders
+func NewPostProcess() *PostProcess {
+	return &PostProcess{
+		passes:  make([]*ShaderPass, 0, 4),
+		enabled: make([]*ShaderPass, 0, 4),
+	}
+}
+
+// Add a shader at the end of the chain. The pass is disabled until enabled.
+func (p *PostProcess) Add(name string, shader *ebiten.Shader, uniforms map[string]any) *ShaderPass {
+	pass := &ShaderPass{
+		Name:     name,
+		Shader:   shader,
+		Uniforms: uniforms,
+	}
+	p.passes = append(p.passes, pass)
+	return pass
+}
+
+// Pass returns the pass by name, or nil if not found
+func (p *PostProcess) Pass(name string) *ShaderPass {
+	for _, pass := range p.passes {
+		if pass.Name == name {
+			return pass
+		}
+	}
+	return nil
+}
+
+// Enabled returns true when at least one pass is enabled
+func (p *PostProcess) Enabled() bool {
+	for _, pass := range p.passes {
+		if pass.Enabled && pass.Shader != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Draw the source image onto the destination through all the enabled passes.
+// The source is copied as it is when no pass is enabled.
+func (p *PostProcess) Draw(dst, src *ebiten.Image) {
+	p.enabled = p.enabled[:0]
+	for _, pass := range p.passes {
+		if pass.Enabled && pass.Shader != nil {
+			p.enabled = append(p.enabled, pass)
+		}
+	}
+	if len(p.enabled) == 0 {
+		dst.DrawImage(src, nil)
+		return
+	}
+	size := src.Bounds().Size()
+	for i := range p.buffers {
+		if len(p.enabled) > i+1 && (p.buffers[i] == nil || p.buffers[i].Bounds().Size() != size) {
+			p.buffers[i] = ebiten.NewImage(size.X, size.Y)
+		}
+	}
+	op := &ebiten.DrawRectShaderOptions{}
+	current := src
+	for i, pass := range p.enabled {
+		target := dst
+		if i < len(p.enabled)-1 {
+			target = p.buffers[i%2]
+			target.Clear()
+		}
+		op.Images[0] = current
+		op.Uniforms = pass.Uniforms
+		target.DrawRectShader(size.X, size.Y, pass.Shader, op)
+		current = target
+	}
+}
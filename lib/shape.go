@@ -0,0 +1,113 @@
+package lib
+
+import "math"
+
+// Shape is an area in screen coordinates, used to detect collisions
+type Shape interface {
+	ContainsPoint(x, y float64) bool
+	Intersects(other Shape) bool
+}
+
+// Rect is a rectangle with its sides parallel to the screen
+type Rect struct {
+	X      float64 // left side
+	Y      float64 // top side
+	Width  float64
+	Height float64
+}
+
+// ContainsPoint returns true when the point is inside the rectangle or on its sides
+func (r Rect) ContainsPoint(x, y float64) bool {
+	return r.X <= x && x <= r.X+r.Width &&
+		r.Y <= y && y <= r.Y+r.Height
+}
+
+// Intersects returns true when the shapes overlap or touch each other
+func (r Rect) Intersects(other Shape) bool {
+	switch o := other.(type) {
+	case Rect:
+		return r.X <= o.X+o.Width && o.X <= r.X+r.Width &&
+			r.Y <= o.Y+o.Height && o.Y <= r.Y+r.Height
+	case Circle:
+		return o.Intersects(r)
+	default:
+		return false
+	}
+}
+
+// Circle is defined by its centre and its radius
+type Circle struct {
+	X      float64
+	Y      float64
+	Radius float64
+}
+
+// ContainsPoint returns true when the point is inside the circle or on its edge
+func (c Circle) ContainsPoint(x, y float64) bool {
+	return distanceSquared(c.X, c.Y, x, y) <= c.Radius*c.Radius
+}
+
+// Intersects returns true when the shapes overlap or touch each other
+func (c Circle) Intersects(other Shape) bool {
+	switch o := other.(type) {
+	case Circle:
+		radius := c.Radius + o.Radius
+		return distanceSquared(c.X, c.Y, o.X, o.Y) <= radius*radius
+	case Rect:
+		// the closest point of the rectangle to the centre of the circle
+		x := clamp(c.X, o.X, o.X+o.Width)
+		y := clamp(c.Y, o.Y, o.Y+o.Height)
+		return c.ContainsPoint(x, y)
+	default:
+		return false
+	}
+}
+
+// Hitbox is the area of a sprite used to detect collisions, relative to the sprite position.
+// It's a circle when the radius is more than 0, a rectangle otherwise.
+type Hitbox struct {
+	X      float64 `json:"x"` // left side of the rectangle, or centre of the circle
+	Y      float64 `json:"y"` // top side of the rectangle, or centre of the circle
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Radius float64 `json:"radius"`
+}
+
+// At returns the area of the hitbox for a sprite at the position
+func (h Hitbox) At(x, y float64) Shape {
+	if h.Radius > 0 {
+		return Circle{X: x + h.X, Y: y + h.Y, Radius: h.Radius}
+	}
+	return Rect{X: x + h.X, Y: y + h.Y, Width: h.Width, Height: h.Height}
+}
+
+// scale returns the hitbox resized around the sprite position. A negative scale mirrors the hitbox.
+func (h Hitbox) scale(scaleX, scaleY float64) Hitbox {
+	if h.Radius > 0 {
+		return Hitbox{
+			X:      h.X * scaleX,
+			Y:      h.Y * scaleY,
+			Radius: h.Radius * math.Max(math.Abs(scaleX), math.Abs(scaleY)),
+		}
+	}
+	return Hitbox{
+		X:      math.Min(h.X*scaleX, (h.X+h.Width)*scaleX),
+		Y:      math.Min(h.Y*scaleY, (h.Y+h.Height)*scaleY),
+		Width:  h.Width * math.Abs(scaleX),
+		Height: h.Height * math.Abs(scaleY),
+	}
+}
+
+func distanceSquared(x1, y1, x2, y2 float64) float64 {
+	return (x2-x1)*(x2-x1) + (y2-y1)*(y2-y1)
+}
+
+func clamp(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
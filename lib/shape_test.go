@@ -0,0 +1,64 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShapeContainsPoint(t *testing.T) {
+	rect := Rect{X: 10, Y: 20, Width: 30, Height: 10}
+	circle := Circle{X: 10, Y: 20, Radius: 5}
+	testData := []struct {
+		name     string
+		shape    Shape
+		x, y     float64
+		contains bool
+	}{
+		{"inside rectangle", rect, 20, 25, true},
+		{"rectangle corner", rect, 40, 30, true},
+		{"left of rectangle", rect, 9, 25, false},
+		{"below rectangle", rect, 20, 31, false},
+		{"circle centre", circle, 10, 20, true},
+		{"circle edge", circle, 13, 24, true},
+		{"outside circle", circle, 14, 24, false},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			assert.Equal(t, testItem.contains, testItem.shape.ContainsPoint(testItem.x, testItem.y))
+		})
+	}
+}
+
+func TestShapeIntersects(t *testing.T) {
+	testData := []struct {
+		name       string
+		a, b       Shape
+		intersects bool
+	}{
+		{"overlapping rectangles", Rect{0, 0, 10, 10}, Rect{5, 5, 10, 10}, true},
+		{"rectangle inside another", Rect{0, 0, 10, 10}, Rect{2, 2, 2, 2}, true},
+		{"touching rectangles", Rect{0, 0, 10, 10}, Rect{10, 0, 10, 10}, true},
+		{"rectangles side by side", Rect{0, 0, 10, 10}, Rect{11, 0, 10, 10}, false},
+		{"rectangles one above the other", Rect{0, 0, 10, 10}, Rect{0, 11, 10, 10}, false},
+		{"overlapping circles", Circle{0, 0, 5}, Circle{8, 0, 5}, true},
+		{"touching circles", Circle{0, 0, 5}, Circle{6, 8, 5}, true},
+		{"distant circles", Circle{0, 0, 5}, Circle{7, 8, 5}, false},
+		{"circle over rectangle side", Circle{-3, 5, 4}, Rect{0, 0, 10, 10}, true},
+		{"circle inside rectangle", Circle{5, 5, 1}, Rect{0, 0, 10, 10}, true},
+		{"rectangle inside circle", Circle{5, 5, 20}, Rect{0, 0, 10, 10}, true},
+		{"circle near rectangle corner", Circle{13, 14, 5}, Rect{0, 0, 10, 10}, true},
+		{"circle outside rectangle corner", Circle{14, 14, 5}, Rect{0, 0, 10, 10}, false},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			assert.Equal(t, testItem.intersects, testItem.a.Intersects(testItem.b))
+			assert.Equal(t, testItem.intersects, testItem.b.Intersects(testItem.a))
+		})
+	}
+}
+
+func TestHitboxAt(t *testing.T) {
+	assert.Equal(t, Rect{X: 90, Y: 40, Width: 20, Height: 10}, Hitbox{X: -10, Y: -10, Width: 20, Height: 10}.At(100, 50))
+	assert.Equal(t, Circle{X: 100, Y: 45, Radius: 6}, Hitbox{Y: -5, Radius: 6}.At(100, 50))
+}
@@ -0,0 +1,101 @@
+package lib
+
+import (
+	"image"
+	"io"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// SheetFace is a font face drawing the glyphs cut from a glyph sheet, in the same format as a BitmapFont.
+// Unlike a BitmapFont, it can be used with the text package, like the fixed size fallback of a TextRenderer.
+type SheetFace struct {
+	sheet   image.Image
+	metrics *fontMetrics
+}
+
+var _ font.Face = (*SheetFace)(nil)
+
+// NewSheetFace creates a font face from a glyph sheet and its metrics file (see BitmapFont for the format).
+// The metrics file can also give the ascent, the distance from the top of the line to the baseline
+// (the whole height by default):
+//
+//	ascent <distance from the top of the line to the baseline>
+//
+// The glyphs are taken from the alpha channel of the sheet.
+func NewSheetFace(sheet image.Image, metrics io.Reader) (*SheetFace, error) {
+	parsed, err := parseFontMetrics(metrics)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.ascent == 0 {
+		parsed.ascent = parsed.height
+	}
+	return &SheetFace{
+		sheet:   sheet,
+		metrics: parsed,
+	}, nil
+}
+
+// Covers returns true when all the characters of the text are in the sheet
+func (f *SheetFace) Covers(text string) bool {
+	for _, char := range text {
+		if _, found := f.metrics.glyphs[char]; !found && char != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+// Close does nothing: the sheet belongs to the caller
+func (f *SheetFace) Close() error {
+	return nil
+}
+
+// Glyph returns the position of the glyph in the sheet, drawn with its top left corner at ascent pixels above the dot
+func (f *SheetFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	glyph, found := f.metrics.glyphs[r]
+	if !found {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	origin := image.Pt(dot.X.Round(), dot.Y.Round()-f.metrics.ascent)
+	dr = image.Rectangle{Min: origin, Max: origin.Add(glyph.Bounds.Size())}
+	return dr, f.sheet, glyph.Bounds.Min, fixed.I(glyph.Advance), true
+}
+
+// GlyphBounds returns the bounds of the glyph relative to the dot
+func (f *SheetFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	glyph, found := f.metrics.glyphs[r]
+	if !found {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	size := glyph.Bounds.Size()
+	bounds = fixed.R(0, -f.metrics.ascent, size.X, size.Y-f.metrics.ascent)
+	return bounds, fixed.I(glyph.Advance), true
+}
+
+// GlyphAdvance returns the horizontal distance to the next glyph
+func (f *SheetFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	glyph, found := f.metrics.glyphs[r]
+	if !found {
+		return 0, false
+	}
+	return fixed.I(glyph.Advance), true
+}
+
+// Kern returns the offset between the two glyphs
+func (f *SheetFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return fixed.I(f.metrics.kerning[[2]rune{r0, r1}])
+}
+
+// Metrics returns the height, ascent and descent of a line of text
+func (f *SheetFace) Metrics() font.Metrics {
+	return font.Metrics{
+		Height:    fixed.I(f.metrics.height),
+		Ascent:    fixed.I(f.metrics.ascent),
+		Descent:   fixed.I(f.metrics.height - f.metrics.ascent),
+		CapHeight: fixed.I(f.metrics.ascent),
+		XHeight:   fixed.I(f.metrics.ascent / 2),
+	}
+}
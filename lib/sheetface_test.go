@@ -0,0 +1,95 @@
+package lib
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// loadTestFace returns the test font, with a sheet where each glyph is filled
+func loadTestFace(t *testing.T, ascent int) *SheetFace {
+	t.Helper()
+	sheet := image.NewNRGBA(image.Rect(0, 0, 50, 20))
+	draw.Draw(sheet, image.Rect(10, 0, 50, 20), image.NewUniform(color.White), image.Point{}, draw.Src)
+	metrics := testMetrics
+	if ascent > 0 {
+		metrics += "ascent " + strconv.Itoa(ascent) + "\n"
+	}
+	face, err := NewSheetFace(sheet, strings.NewReader(metrics))
+	require.NoError(t, err)
+	return face
+}
+
+func TestSheetFaceMetrics(t *testing.T) {
+	testData := []struct {
+		name     string
+		ascent   int
+		expected font.Metrics
+	}{
+		{"default ascent", 0, font.Metrics{Height: fixed.I(20), Ascent: fixed.I(20), Descent: 0, CapHeight: fixed.I(20), XHeight: fixed.I(10)}},
+		{"ascent", 16, font.Metrics{Height: fixed.I(20), Ascent: fixed.I(16), Descent: fixed.I(4), CapHeight: fixed.I(16), XHeight: fixed.I(8)}},
+	}
+
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			face := loadTestFace(t, testItem.ascent)
+			assert.Equal(t, testItem.expected, face.Metrics())
+		})
+	}
+}
+
+func TestSheetFaceGlyph(t *testing.T) {
+	face := loadTestFace(t, 16)
+
+	dr, mask, maskp, advance, ok := face.Glyph(fixed.P(100, 50), 'B')
+	require.True(t, ok)
+	assert.Equal(t, image.Rect(100, 34, 110, 54), dr)
+	assert.NotNil(t, mask)
+	assert.Equal(t, image.Pt(30, 0), maskp)
+	assert.Equal(t, fixed.I(10), advance)
+
+	bounds, advance, ok := face.GlyphBounds(' ')
+	require.True(t, ok)
+	assert.Equal(t, fixed.R(0, -16, 1, -15), bounds)
+	assert.Equal(t, fixed.I(5), advance)
+
+	_, _, _, _, ok = face.Glyph(fixed.P(0, 0), 'Z')
+	assert.False(t, ok)
+	_, ok = face.GlyphAdvance('Z')
+	assert.False(t, ok)
+
+	assert.Equal(t, fixed.I(-2), face.Kern('A', 'V'))
+	assert.Equal(t, fixed.I(0), face.Kern('V', 'A'))
+}
+
+func TestSheetFaceDrawer(t *testing.T) {
+	face := loadTestFace(t, 16)
+	dst := image.NewAlpha(image.Rect(0, 0, 40, 20))
+	drawer := &font.Drawer{
+		Dst:  dst,
+		Src:  image.Opaque,
+		Face: face,
+		Dot:  fixed.P(0, 16),
+	}
+	assert.Equal(t, fixed.I(18), drawer.MeasureString("AV"))
+	drawer.DrawString("AV")
+
+	// A from 0 to 10, then V from 8 to 18
+	assert.Equal(t, uint8(0xff), dst.AlphaAt(0, 0).A)
+	assert.Equal(t, uint8(0xff), dst.AlphaAt(17, 19).A)
+	assert.Equal(t, uint8(0), dst.AlphaAt(18, 0).A)
+}
+
+func TestSheetFaceCovers(t *testing.T) {
+	face := loadTestFace(t, 0)
+	assert.True(t, face.Covers("AB V\nBA"))
+	assert.False(t, face.Covers("ABC"))
+}
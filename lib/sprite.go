@@ -2,32 +2,46 @@ package lib
 
 import (
 	"fmt"
+	"image/color"
 	"log"
-	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
 )
 
-// SequenceFunc is used as a callback to decide which image to draw
-type SequenceFunc func(int) int
-
 // Sprite manages sprite movement and animation
 type Sprite struct {
-	xType        XType
-	yType        YType
-	x            float64
-	y            float64
-	frame        int             // current frame counter (animation mode)
-	width        int             // fixed size only
-	height       int             // fixed size only
-	image        *ebiten.Image   // single image (no animation)
-	animation    []*ebiten.Image // animation images
-	sequence     []int           // animation sequence (in case same images need reused)
-	sequenceFunc SequenceFunc    // use a callback to decide on animation sequence instead
-	rate         int             // change image every n frame per second
-	loop         bool            // animation loop
-	started      bool            // is animation running?
-	op           *ebiten.DrawImageOptions
+	xType      XType
+	yType      YType
+	x          float64
+	y          float64
+	width      int     // fixed size only
+	height     int     // fixed size only
+	pivotX     float64 // offset of the position from the anchor of xType
+	pivotY     float64 // offset of the position from the anchor of yType
+	hitbox     *Hitbox // hitbox of the frames without one, nil to use the image rectangle
+	flipX      bool    // mirror the image horizontally around the position
+	flipY      bool    // mirror the image vertically around the position
+	scaleX     float64
+	scaleY     float64
+	angle      float64        // rotation around the position, in radians (clockwise)
+	alpha      float64        // opacity from 0 (transparent) to 1 (opaque)
+	tint       color.Color    // multiplies the colours of the image, nil for none
+	colorM     *colorm.ColorM // colour matrix applied before the tint and alpha, nil for none
+	image      *ebiten.Image  // current image
+	playing    *Animation     // current animation, nil if none
+	step       int            // position in the frames of the animation, in the order they're played
+	ticks      int            // number of ticks the current frame has been displayed
+	started    bool           // is animation running?
+	paused     bool           // animation is running, but stays on the current frame
+	looped     bool           // animation went back to its loop position at least once
+	revision   int            // revision of the animation played, to notice when it's reloaded
+	events     []string       // events reached by the animation, until drained by Events
+	previousX  float64        // position before the current tick, to draw the sprite in between (see SetInterpolation)
+	previousY  float64
+	movedTick  uint64 // last tick the sprite moved
+	positioned bool   // the sprite has been moved at least once
+	op         *ebiten.DrawImageOptions
 }
 
 // NewSprite creates a new Sprite with default coordinate type
@@ -37,6 +51,9 @@ func NewSprite(xType XType, yType YType) *Sprite {
 		yType:   yType,
 		op:      &ebiten.DrawImageOptions{},
 		started: false,
+		scaleX:  1,
+		scaleY:  1,
+		alpha:   1,
 	}
 }
 
@@ -45,7 +62,7 @@ func (s *Sprite) String() string {
 	return fmt.Sprintf("x: %.1f, y: %.1f, frame: %d",
 		s.x,
 		s.y,
-		s.frame,
+		s.Frame(),
 	)
 }
 
@@ -62,20 +79,34 @@ func (s *Sprite) SetSize(width, height int) *Sprite {
 	return s
 }
 
-// Update animation (if needed)
+// Update animation (if needed): moves to the next frame once the current frame has been displayed for its duration
 func (s *Sprite) Update() {
-	if !s.started {
+	if !s.started || s.paused {
 		return
 	}
-	s.frame++
-	if !s.loop && s.frame >= s.rate*len(s.animation) {
-		// animation is finished
-		s.started = false
+	s.checkReload()
+	steps := s.playing.steps()
+	s.ticks++
+	if s.ticks < s.playing.Frames[s.playing.frameAt(s.step)].Duration {
 		return
 	}
-	// update current frame
-	frameID := s.getFrameID()
-	s.image = s.animation[frameID]
+	s.ticks = 0
+	s.step++
+	if s.step >= steps {
+		if !s.playing.Loop {
+			// animation is finished: stay on the last frame
+			s.step = steps - 1
+			s.started = false
+			s.queueEvent(EventDone)
+			return
+		}
+		s.looped = true
+		s.step = s.playing.LoopFrom
+		if s.step >= steps {
+			s.step = 0
+		}
+	}
+	s.showFrame()
 }
 
 // Draw the current image, or the animation to the screen. If no image or animation has been set, it does nothing
@@ -85,87 +116,259 @@ func (s *Sprite) Draw(screen *ebiten.Image) {
 		return
 	}
 	width, height := s.image.Size()
-	s.op.GeoM.Reset()
-	s.op.GeoM.Translate(s.xleft(float64(width)), s.ytop(float64(height)))
+	s.op.GeoM = s.geoM(float64(width), float64(height))
+	if s.colorM != nil {
+		colorM := *s.colorM
+		if s.tint != nil {
+			colorM.ScaleWithColor(s.tint)
+		}
+		colorM.Scale(1, 1, 1, s.alpha)
+		colorm.DrawImage(screen, s.image, colorM, &colorm.DrawImageOptions{GeoM: s.op.GeoM})
+		return
+	}
+	s.op.ColorScale.Reset()
+	if s.tint != nil {
+		s.op.ColorScale.ScaleWithColor(s.tint)
+	}
+	s.op.ColorScale.ScaleAlpha(float32(s.alpha))
 	screen.DrawImage(s.image, s.op)
 }
 
-// Start (or restart) an animation
-func (s *Sprite) Start() *Sprite {
-	// only start if the animation is well defined
-	if s.animation != nil && len(s.animation) > 0 && s.rate > 0 {
-		s.frame = 0
-		s.started = true
+// geoM returns the transformations from the image to the screen
+func (s *Sprite) geoM(width, height float64) ebiten.GeoM {
+	scaleX, scaleY := s.drawScale()
+	geoM := ebiten.GeoM{}
+	// the position is the origin of all the transformations
+	geoM.Translate(-s.xType.offset(width)-s.pivotX, -s.yType.offset(height)-s.pivotY)
+	geoM.Scale(scaleX, scaleY)
+	if s.angle != 0 {
+		geoM.Rotate(s.angle)
 	}
+	geoM.Translate(s.drawPosition())
+	return geoM
+}
+
+// SetFlip mirrors the image horizontally and/or vertically around the position.
+// It is combined with the flip of the animation played.
+func (s *Sprite) SetFlip(flipX, flipY bool) *Sprite {
+	s.flipX = flipX
+	s.flipY = flipY
+	return s
+}
+
+// SetScale resizes the image around the position (1 is the size of the image)
+func (s *Sprite) SetScale(scaleX, scaleY float64) *Sprite {
+	s.scaleX = scaleX
+	s.scaleY = scaleY
+	return s
+}
+
+// SetRotation rotates the image around the position, in radians (clockwise).
+// The rotation is only drawn: it doesn't change the coordinates nor the hit area.
+func (s *Sprite) SetRotation(angle float64) *Sprite {
+	s.angle = angle
+	return s
+}
+
+// SetAlpha sets the opacity of the image, from 0 (transparent) to 1 (opaque)
+func (s *Sprite) SetAlpha(alpha float64) *Sprite {
+	s.alpha = alpha
+	return s
+}
+
+// SetTint multiplies the colours of the image by the colour (nil to remove the tint)
+func (s *Sprite) SetTint(tint color.Color) *Sprite {
+	s.tint = tint
+	return s
+}
+
+// SetColorMatrix transforms the colours of the image with the matrix (nil to remove it).
+// The tint and alpha are applied after the matrix.
+func (s *Sprite) SetColorMatrix(colorM *colorm.ColorM) *Sprite {
+	s.colorM = colorM
 	return s
 }
 
+// Play starts the animation from its first frame
+func (s *Sprite) Play(animation *Animation) *Sprite {
+	if animation == nil || len(animation.Frames) == 0 {
+		return s
+	}
+	s.playing = animation
+	s.revision = animation.revision
+	s.step = 0
+	s.ticks = 0
+	s.started = true
+	s.paused = false
+	s.looped = false
+	s.showFrame()
+	return s
+}
+
+// Switch plays the animation, unless it is already playing: calling it on each update carries on the animation
+func (s *Sprite) Switch(animation *Animation) *Sprite {
+	if s.playing == animation && s.started {
+		return s
+	}
+	return s.Play(animation)
+}
+
+// Playing returns the animation played (or last played), nil if none
+func (s *Sprite) Playing() *Animation {
+	return s.playing
+}
+
+// Start (or restart) the animation
+func (s *Sprite) Start() *Sprite {
+	return s.Play(s.playing)
+}
+
 // Stop animation
 func (s *Sprite) Stop() *Sprite {
 	s.started = false
 	return s
 }
 
-// Animation defines a new animation (but does not start it yet)
-func (s *Sprite) Animation(animation []*ebiten.Image, sequence []int, rate int, loop bool) *Sprite {
-	s.animation = animation
-	s.sequence = sequence
-	s.rate = rate
-	s.loop = loop
-	if s.animation != nil && len(s.animation) > 0 {
-		s.image = s.animation[0]
+// Pause the animation on the current frame. A paused animation is not finished.
+func (s *Sprite) Pause() *Sprite {
+	s.paused = true
+	return s
+}
+
+// Resume the animation where it was paused
+func (s *Sprite) Resume() *Sprite {
+	s.paused = false
+	return s
+}
+
+// IsPaused returns true when the animation is paused
+func (s *Sprite) IsPaused() bool {
+	return s.paused
+}
+
+// Seek displays the frame (an index in the frames of the animation) from the start of its duration.
+// The animation carries on from there when it's running. The events of the frame are sent again.
+func (s *Sprite) Seek(frame int) *Sprite {
+	if s.playing == nil || frame < 0 || frame >= len(s.playing.Frames) {
+		return s
+	}
+	for step := 0; step < s.playing.steps(); step++ {
+		if s.playing.frameAt(step) == frame {
+			s.step = step
+			s.ticks = 0
+			s.showFrame()
+			break
+		}
 	}
 	return s
 }
 
-// Animate defines a new animation and starts it
-func (s *Sprite) Animate(animation []*ebiten.Image, sequence []int, rate int, loop bool) *Sprite {
-	s.Animation(animation, sequence, rate, loop)
-	return s.Start()
+// Frame returns the index of the current frame in the frames of the animation, -1 when there's no animation
+func (s *Sprite) Frame() int {
+	if s.playing == nil || len(s.playing.Frames) == 0 {
+		return -1
+	}
+	s.checkReload()
+	return s.playing.frameAt(s.step)
+}
+
+// Events returns the names of the events reached by the animation since the last call, in order.
+// The owner of the sprite should call it after each Update.
+func (s *Sprite) Events() []string {
+	if len(s.events) == 0 {
+		return nil
+	}
+	events := s.events
+	s.events = nil
+	return events
 }
 
-// SetSequenceFunc registers a callback to calculate the next image.
-// the value returned should be a valid index in the animation slice passed to the Animation method
-func (s *Sprite) SetSequenceFunc(sequenceFunc SequenceFunc) *Sprite {
-	s.sequenceFunc = sequenceFunc
+// Animation defines a new animation from a list of images (but does not start it yet).
+// The sequence is the list of indexes in images of each frame: when nil, each image is a frame.
+// Each frame is displayed for rate ticks.
+func (s *Sprite) Animation(images []*ebiten.Image, sequence []int, rate int, loop bool) *Sprite {
+	if len(sequence) > 0 {
+		frames := make([]*ebiten.Image, len(sequence))
+		for i, index := range sequence {
+			frames[i] = images[index]
+		}
+		images = frames
+	}
+	s.playing = NewAnimation("", images, rate, loop)
+	s.revision = 0
+	s.step = 0
+	s.ticks = 0
+	s.started = false
+	if len(images) > 0 {
+		s.image = images[0]
+	}
 	return s
 }
 
+// Animate defines a new animation from a list of images and starts it
+func (s *Sprite) Animate(images []*ebiten.Image, sequence []int, rate int, loop bool) *Sprite {
+	s.Animation(images, sequence, rate, loop)
+	return s.Start()
+}
+
 // IsFinished returns true when the *Sprite animation has finished.
 // An animation with loop = true will never finish
 func (s *Sprite) IsFinished() bool {
 	return !s.started
 }
 
+// showFrame displays the image of the current step, and queues the events of its frame
+func (s *Sprite) showFrame() {
+	frame := s.playing.frameAt(s.step)
+	s.image = s.playing.Frames[frame].Image
+	s.playing.eventsAt(frame, s.looped, s.queueEvent)
+}
+
+// checkReload goes back to the first frame when the animation was reloaded with fewer frames than the current step
+func (s *Sprite) checkReload() {
+	if s.revision == s.playing.revision {
+		return
+	}
+	s.revision = s.playing.revision
+	if s.step < s.playing.steps() {
+		return
+	}
+	s.step = 0
+	s.ticks = 0
+	if len(s.playing.Frames) > 0 {
+		s.image = s.playing.Frames[s.playing.frameAt(s.step)].Image
+	}
+}
+
+func (s *Sprite) queueEvent(name string) {
+	s.events = append(s.events, name)
+}
+
+// SetPivot moves the point of the image at the sprite position: it's an offset from the anchor
+// of the coordinate types defined at instantiation (a positive pivotX moves the point to the right)
+func (s *Sprite) SetPivot(pivotX, pivotY float64) *Sprite {
+	s.pivotX = pivotX
+	s.pivotY = pivotY
+	return s
+}
+
 // Move to relative coordinates (adds coordinates to the current position)
 func (s *Sprite) Move(x, y float64) *Sprite {
-	s.x += x
-	s.y += y
+	s.setPosition(s.x+x, s.y+y)
 	return s
 }
 
-// MoveTo the new coordinates using the default coordinates type defined at instantiation
+// MoveTo the new coordinates using the default coordinates type defined at instantiation (and the pivot)
 func (s *Sprite) MoveTo(x, y float64) *Sprite {
-	s.x = x
-	s.y = y
+	s.setPosition(x, y)
 	return s
 }
 
-// MoveToType moves to the new coordinates using the specified coordinate types
+// MoveToType moves the point of the image at the specified coordinate types to the new coordinates
+// (without the pivot: MoveToType(x, y, XCentre, YCentre) always moves the centre of the image).
 func (s *Sprite) MoveToType(x, y float64, xType XType, yType YType) *Sprite {
-	if s.xType == xType {
-		s.x = x
-	} else {
-		panic(fmt.Sprintf("mixing different types of coordinates is not yet supported: want to set %s but is %s", xType.String(), s.xType.String()))
-	}
-	if s.yType == yType {
-		s.y = y
-	} else if s.yType == YCentre && yType == YBottom {
-		_, height := s.image.Size()
-		s.y = y - (float64(height) / 2)
-	} else {
-		panic(fmt.Sprintf("mixing different types of coordinates is not yet supported: want to set %s but is %s", yType.String(), s.yType.String()))
-	}
+	width, height := s.size()
+	s.setPosition(x-xType.offset(width)+s.positionX(width), y-yType.offset(height)+s.positionY(height))
 	return s
 }
 
@@ -179,124 +382,105 @@ func (s *Sprite) RawY() float64 {
 	return s.y
 }
 
-// X returns x position. If no image is available to calculate width, it returns -1
+// X returns x position of the image (as drawn, without the rotation) at the coordinate type
 func (s *Sprite) X(xType XType) float64 {
-	if s.image == nil {
-		return -1
-	}
-	width, _ := s.image.Size()
-	switch xType {
-	case XCentre:
-		return s.xcentre(float64(width))
-	case XRight:
-		return s.xright(float64(width))
-	default:
-		return s.xleft(float64(width))
-	}
+	width, _ := s.size()
+	return s.left(width) + xType.offset(width)
 }
 
-// Y returns y position. If no image is available to calculate height, it returns -1
+// Y returns y position of the image (as drawn, without the rotation) at the coordinate type
 func (s *Sprite) Y(yType YType) float64 {
-	if s.image == nil {
-		return -1
+	_, height := s.size()
+	return s.top(height) + yType.offset(height)
+}
+
+// SetHitbox sets the hitbox used when the current frame doesn't have one. A nil hitbox uses the image rectangle.
+func (s *Sprite) SetHitbox(hitbox *Hitbox) *Sprite {
+	s.hitbox = hitbox
+	return s
+}
+
+// HitArea returns the area of the sprite used to detect collisions: the hitbox of the current frame,
+// or the hitbox of the sprite, or the image rectangle when there's no hitbox
+func (s *Sprite) HitArea() Shape {
+	scaleX, scaleY := s.drawScale()
+	if s.playing != nil && len(s.playing.Frames) > 0 {
+		if hitbox := s.playing.Frames[s.Frame()].Hitbox; hitbox != nil {
+			return hitbox.scale(scaleX, scaleY).At(s.x, s.y)
+		}
 	}
-	_, height := s.image.Size()
-	switch yType {
-	case YCentre:
-		return s.ycentre(float64(height))
-	case YBottom:
-		return s.ybottom(float64(height))
-	default:
-		return s.ytop(float64(height))
+	if s.hitbox != nil {
+		return s.hitbox.scale(scaleX, scaleY).At(s.x, s.y)
 	}
+	width, height := s.size()
+	return Rect{X: s.left(width), Y: s.top(height), Width: width, Height: height}
 }
 
-// CollidePoint returns true when the coordinates are "touching" the sprite
+// CollidePoint returns true when the coordinates are "touching" the hit area of the sprite
 func (s *Sprite) CollidePoint(x, y float64) bool {
-	return s.X(XLeft) <= x && x <= s.X(XRight) &&
-		s.Y(YTop) <= y && y <= s.Y(YBottom)
+	return s.HitArea().ContainsPoint(x, y)
 }
 
-func (s *Sprite) getFrameID() int {
-	if s.sequenceFunc != nil {
-		return s.sequenceFunc(s.frame)
-	}
-	current := float64(s.frame / s.rate)
-	if s.sequence == nil || len(s.sequence) == 0 {
-		// no sequence, we just go through all images one by one
-		frameID := int(math.Mod(current, float64(len(s.animation))))
-		return frameID
-	}
-	frameID := int(math.Mod(current, float64(len(s.sequence))))
-	// if frameID is over the size of animation, we pick the last one
-	if frameID >= len(s.animation) {
-		frameID = len(s.animation) - 1
-	}
-	return frameID
+// Collides returns true when the hit areas of the sprites are "touching" each other
+func (s *Sprite) Collides(other *Sprite) bool {
+	return s.HitArea().Intersects(other.HitArea())
 }
 
-func (s *Sprite) xleft(width float64) float64 {
-	switch s.xType {
-	case XCentre:
-		return s.x - (width / 2)
-	case XRight:
-		return s.x - width
-	default:
-		return s.x
+// size returns the size forced by SetSize, or the size of the current image, multiplied by the scale.
+// A sprite without any image has no size: all its coordinate types are at the same position.
+func (s *Sprite) size() (float64, float64) {
+	width, height := float64(s.width), float64(s.height)
+	if s.width == 0 && s.height == 0 {
+		if s.image == nil {
+			return 0, 0
+		}
+		w, h := s.image.Size()
+		width, height = float64(w), float64(h)
 	}
+	return width * s.scaleX, height * s.scaleY
 }
 
-func (s *Sprite) xcentre(width float64) float64 {
-	switch s.xType {
-	case XCentre:
-		return s.x
-	case XRight:
-		return s.x - (width / 2)
-	default:
-		return s.x + (width / 2)
+// drawScale returns the scale, negative when the image is flipped
+func (s *Sprite) drawScale() (float64, float64) {
+	scaleX, scaleY := s.scaleX, s.scaleY
+	flipX, flipY := s.flipX, s.flipY
+	if s.playing != nil {
+		flipX = flipX != s.playing.FlipX
+		flipY = flipY != s.playing.FlipY
+	}
+	if flipX {
+		scaleX = -scaleX
+	}
+	if flipY {
+		scaleY = -scaleY
 	}
+	return scaleX, scaleY
 }
 
-func (s *Sprite) xright(width float64) float64 {
-	switch s.xType {
-	case XCentre:
-		return s.x + (width / 2)
-	case XRight:
-		return s.x
-	default:
-		return s.x + width
+// positionX returns the distance from the left of the image (as drawn) to the position
+func (s *Sprite) positionX(width float64) float64 {
+	scaleX, _ := s.drawScale()
+	position := s.xType.offset(width) + s.pivotX*s.scaleX
+	if scaleX < 0 {
+		return width - position
 	}
+	return position
 }
 
-func (s *Sprite) ytop(height float64) float64 {
-	switch s.yType {
-	case YCentre:
-		return s.y - (height / 2)
-	case YBottom:
-		return s.y - height
-	default:
-		return s.y
+// positionY returns the distance from the top of the image (as drawn) to the position
+func (s *Sprite) positionY(height float64) float64 {
+	_, scaleY := s.drawScale()
+	position := s.yType.offset(height) + s.pivotY*s.scaleY
+	if scaleY < 0 {
+		return height - position
 	}
+	return position
 }
 
-func (s *Sprite) ycentre(height float64) float64 {
-	switch s.yType {
-	case YCentre:
-		return s.y
-	case YBottom:
-		return s.y - (height / 2)
-	default:
-		return s.y + (height / 2)
-	}
+func (s *Sprite) left(width float64) float64 {
+	return s.x - s.positionX(width)
 }
 
-func (s *Sprite) ybottom(height float64) float64 {
-	switch s.yType {
-	case YCentre:
-		return s.y + (height / 2)
-	case YBottom:
-		return s.y
-	default:
-		return s.y + height
-	}
+func (s *Sprite) top(height float64) float64 {
+	return s.y - s.positionY(height)
 }
@@ -0,0 +1,493 @@
+package lib
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// testFrames returns an animation of count frames with the durations (the same duration for all when only one is given)
+func testFrames(count int, durations ...int) []Frame {
+	frames := make([]Frame, count)
+	for i := range frames {
+		duration := durations[0]
+		if len(durations) == count {
+			duration = durations[i]
+		}
+		frames[i] = Frame{Image: new(ebiten.Image), Duration: duration}
+	}
+	return frames
+}
+
+// framesPlayed returns the current frame of the sprite, then after each update
+func framesPlayed(sprite *Sprite, updates int) []int {
+	played := make([]int, updates+1)
+	played[0] = sprite.Frame()
+	for i := 1; i <= updates; i++ {
+		sprite.Update()
+		played[i] = sprite.Frame()
+	}
+	return played
+}
+
+func TestSpriteAnimationModes(t *testing.T) {
+	testData := []struct {
+		name      string
+		animation *Animation
+		played    []int
+		finished  bool
+	}{
+		{
+			name:      "forward once",
+			animation: &Animation{Frames: testFrames(3, 1)},
+			played:    []int{0, 1, 2, 2, 2},
+			finished:  true,
+		},
+		{
+			name:      "forward loop",
+			animation: &Animation{Frames: testFrames(3, 1), Loop: true},
+			played:    []int{0, 1, 2, 0, 1, 2, 0},
+		},
+		{
+			name:      "forward loop from the second frame",
+			animation: &Animation{Frames: testFrames(3, 1), Loop: true, LoopFrom: 1},
+			played:    []int{0, 1, 2, 1, 2, 1},
+		},
+		{
+			name:      "same duration",
+			animation: &Animation{Frames: testFrames(2, 3), Loop: true},
+			played:    []int{0, 0, 0, 1, 1, 1, 0},
+		},
+		{
+			name:      "per frame durations",
+			animation: &Animation{Frames: testFrames(3, 1, 3, 2)},
+			played:    []int{0, 1, 1, 1, 2, 2, 2},
+			finished:  true,
+		},
+		{
+			name:      "reverse once",
+			animation: &Animation{Frames: testFrames(3, 1), Mode: Reverse},
+			played:    []int{2, 1, 0, 0},
+			finished:  true,
+		},
+		{
+			name:      "reverse loop",
+			animation: &Animation{Frames: testFrames(3, 1), Mode: Reverse, Loop: true},
+			played:    []int{2, 1, 0, 2, 1},
+		},
+		{
+			name:      "ping-pong once",
+			animation: &Animation{Frames: testFrames(3, 1), Mode: PingPong},
+			played:    []int{0, 1, 2, 1, 0, 0},
+			finished:  true,
+		},
+		{
+			name:      "ping-pong loop",
+			animation: &Animation{Frames: testFrames(3, 1), Mode: PingPong, Loop: true},
+			played:    []int{0, 1, 2, 1, 0, 1, 2, 1, 0},
+		},
+		{
+			name:      "ping-pong single frame",
+			animation: &Animation{Frames: testFrames(1, 1), Mode: PingPong, Loop: true},
+			played:    []int{0, 0, 0},
+		},
+		{
+			name:      "ping-pong durations",
+			animation: &Animation{Frames: testFrames(3, 1, 2, 3), Mode: PingPong},
+			played:    []int{0, 1, 1, 2, 2, 2, 1, 1, 0, 0},
+			finished:  true,
+		},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			sprite := NewSprite(XLeft, YTop).Play(testItem.animation)
+			played := framesPlayed(sprite, len(testItem.played)-1)
+			assert.Equal(t, testItem.played, played)
+			assert.Equal(t, testItem.finished, sprite.IsFinished())
+			assert.Equal(t, testItem.animation.Frames[played[len(played)-1]].Image, sprite.image)
+		})
+	}
+}
+
+func TestAnimationLength(t *testing.T) {
+	testData := []struct {
+		name      string
+		animation *Animation
+		length    int
+	}{
+		{"forward", &Animation{Frames: testFrames(3, 1, 2, 3)}, 6},
+		{"reverse", &Animation{Frames: testFrames(3, 1, 2, 3), Mode: Reverse}, 6},
+		{"ping-pong once", &Animation{Frames: testFrames(3, 1, 2, 3), Mode: PingPong}, 9},
+		{"ping-pong loop", &Animation{Frames: testFrames(3, 1, 2, 3), Mode: PingPong, Loop: true}, 8},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			assert.Equal(t, testItem.length, testItem.animation.Length())
+		})
+	}
+}
+
+func TestSpriteImageSequence(t *testing.T) {
+	images := []*ebiten.Image{new(ebiten.Image), new(ebiten.Image), new(ebiten.Image)}
+	testData := []struct {
+		name     string
+		sequence []int
+		loop     bool
+		shown    []int // index of the image shown, then after each update
+	}{
+		{"no sequence", nil, true, []int{0, 0, 1, 1, 2, 2, 0}},
+		{"sequence", []int{0, 1, 2, 1}, true, []int{0, 0, 1, 1, 2, 2, 1, 1, 0}},
+		{"sequence once", []int{2, 0}, false, []int{2, 2, 0, 0, 0}},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			sprite := NewSprite(XLeft, YTop).Animate(images, testItem.sequence, 2, testItem.loop)
+			shown := make([]int, len(testItem.shown))
+			for i := range shown {
+				if i > 0 {
+					sprite.Update()
+				}
+				shown[i] = indexOf(images, sprite.image)
+			}
+			assert.Equal(t, testItem.shown, shown)
+			assert.Equal(t, !testItem.loop, sprite.IsFinished())
+		})
+	}
+}
+
+func indexOf(images []*ebiten.Image, image *ebiten.Image) int {
+	for i, img := range images {
+		if img == image {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestSpriteAnimationNotStarted(t *testing.T) {
+	images := []*ebiten.Image{new(ebiten.Image), new(ebiten.Image)}
+	sprite := NewSprite(XLeft, YTop).Animation(images, nil, 1, true)
+	assert.Equal(t, images[0], sprite.image)
+	assert.True(t, sprite.IsFinished())
+	assert.Equal(t, []int{0, 0, 0}, framesPlayed(sprite, 2))
+
+	sprite.Start()
+	assert.Equal(t, []int{0, 1, 0}, framesPlayed(sprite, 2))
+
+	// no image: nothing to play
+	sprite = NewSprite(XLeft, YTop).Animate(nil, nil, 1, true)
+	assert.True(t, sprite.IsFinished())
+	assert.Equal(t, -1, sprite.Frame())
+}
+
+func TestSpritePauseResume(t *testing.T) {
+	sprite := NewSprite(XLeft, YTop).Play(&Animation{Frames: testFrames(3, 2)})
+	assert.Equal(t, []int{0, 0, 1}, framesPlayed(sprite, 2))
+
+	sprite.Pause()
+	assert.True(t, sprite.IsPaused())
+	assert.False(t, sprite.IsFinished())
+	assert.Equal(t, []int{1, 1, 1, 1}, framesPlayed(sprite, 3))
+
+	// carries on with the remaining ticks of the frame
+	sprite.Resume()
+	assert.False(t, sprite.IsPaused())
+	assert.Equal(t, []int{1, 1, 2, 2, 2}, framesPlayed(sprite, 4))
+	assert.True(t, sprite.IsFinished())
+
+	// playing again resumes the animation
+	sprite.Pause().Play(sprite.Playing())
+	assert.False(t, sprite.IsPaused())
+	assert.Equal(t, []int{0, 0, 1}, framesPlayed(sprite, 2))
+}
+
+func TestSpriteSeek(t *testing.T) {
+	testData := []struct {
+		name      string
+		animation *Animation
+		seek      int
+		played    []int
+	}{
+		{"forward", &Animation{Frames: testFrames(4, 2), Loop: true}, 2, []int{2, 2, 3, 3, 0}},
+		{"reverse", &Animation{Frames: testFrames(4, 2), Mode: Reverse, Loop: true}, 2, []int{2, 2, 1, 1, 0}},
+		{"ping-pong", &Animation{Frames: testFrames(4, 1), Mode: PingPong, Loop: true}, 2, []int{2, 3, 2, 1, 0}},
+		{"outside", &Animation{Frames: testFrames(4, 1), Loop: true}, 4, []int{1, 2, 3}},
+		{"negative", &Animation{Frames: testFrames(4, 1), Loop: true}, -1, []int{1, 2, 3}},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			sprite := NewSprite(XLeft, YTop).Play(testItem.animation)
+			sprite.Update()
+			sprite.Seek(testItem.seek)
+			assert.Equal(t, testItem.played, framesPlayed(sprite, len(testItem.played)-1))
+		})
+	}
+}
+
+func TestSpriteSeekWhenFinished(t *testing.T) {
+	animation := &Animation{Frames: testFrames(3, 1), Events: []AnimationEvent{{Frame: 1, Name: "step"}}}
+	sprite := NewSprite(XLeft, YTop).Play(animation)
+	framesPlayed(sprite, 3)
+	assert.True(t, sprite.IsFinished())
+	sprite.Events()
+
+	// the frame is displayed, but the animation doesn't start again
+	sprite.Seek(1)
+	assert.Equal(t, []int{1, 1}, framesPlayed(sprite, 1))
+	assert.Equal(t, []string{"step"}, sprite.Events())
+}
+
+func TestSpriteEventsInReverse(t *testing.T) {
+	animation := &Animation{
+		Frames: testFrames(3, 1),
+		Mode:   Reverse,
+		Events: []AnimationEvent{{Frame: 0, Name: "last"}, {Frame: 2, Name: "first"}},
+	}
+	sprite := NewSprite(XLeft, YTop).Play(animation)
+	framesPlayed(sprite, 3)
+	assert.Equal(t, []string{"first", "last", EventDone}, sprite.Events())
+}
+
+func TestSpriteMoveToType(t *testing.T) {
+	xTypes := []XType{XLeft, XCentre, XRight}
+	yTypes := []YType{YTop, YCentre, YBottom}
+	// a 20x10 sprite with its top left corner at 100, 50
+	xs := map[XType]float64{XLeft: 100, XCentre: 110, XRight: 120}
+	ys := map[YType]float64{YTop: 50, YCentre: 55, YBottom: 60}
+
+	for _, spriteX := range xTypes {
+		for _, spriteY := range yTypes {
+			for _, moveX := range xTypes {
+				for _, moveY := range yTypes {
+					name := spriteX.String() + " " + spriteY.String() + " moved to " + moveX.String() + " " + moveY.String()
+					t.Run(name, func(t *testing.T) {
+						sprite := NewSprite(spriteX, spriteY).SetSize(20, 10)
+						sprite.MoveToType(xs[moveX], ys[moveY], moveX, moveY)
+						assert.Equal(t, xs[spriteX], sprite.RawX())
+						assert.Equal(t, ys[spriteY], sprite.RawY())
+						for _, xType := range xTypes {
+							assert.Equal(t, xs[xType], sprite.X(xType), xType.String())
+						}
+						for _, yType := range yTypes {
+							assert.Equal(t, ys[yType], sprite.Y(yType), yType.String())
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestSpritePivot(t *testing.T) {
+	testData := []struct {
+		name           string
+		xType          XType
+		yType          YType
+		pivotX, pivotY float64
+		left, top      float64 // top left corner of the 20x10 sprite moved to 100, 50
+	}{
+		{"left top", XLeft, YTop, 0, 0, 100, 50},
+		{"left top with pivot", XLeft, YTop, 5, 2, 95, 48},
+		{"centre bottom", XCentre, YBottom, 0, 0, 90, 40},
+		{"centre bottom with pivot", XCentre, YBottom, -4, -3, 94, 43},
+		{"right centre with pivot", XRight, YCentre, -20, 5, 100, 40},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			sprite := NewSprite(testItem.xType, testItem.yType).SetSize(20, 10).SetPivot(testItem.pivotX, testItem.pivotY)
+			sprite.MoveTo(100, 50)
+			assert.Equal(t, testItem.left, sprite.X(XLeft))
+			assert.Equal(t, testItem.top, sprite.Y(YTop))
+			assert.Equal(t, testItem.left+10, sprite.X(XCentre))
+			assert.Equal(t, testItem.top+10, sprite.Y(YBottom))
+
+			// moving by type ignores the pivot
+			sprite.MoveToType(200, 100, XCentre, YCentre)
+			assert.Equal(t, 200.0, sprite.X(XCentre))
+			assert.Equal(t, 100.0, sprite.Y(YCentre))
+			// and the position is still the pivot
+			assert.Equal(t, 190+testItem.xType.offset(20)+testItem.pivotX, sprite.RawX())
+			assert.Equal(t, 95+testItem.yType.offset(10)+testItem.pivotY, sprite.RawY())
+		})
+	}
+}
+
+func TestSpriteWithoutImage(t *testing.T) {
+	sprite := NewSprite(XCentre, YBottom).MoveToType(30, 40, XLeft, YTop)
+	assert.Equal(t, 30.0, sprite.X(XLeft))
+	assert.Equal(t, 30.0, sprite.X(XRight))
+	assert.Equal(t, 40.0, sprite.Y(YCentre))
+	assert.True(t, sprite.CollidePoint(30, 40))
+	assert.False(t, sprite.CollidePoint(31, 40))
+}
+
+func TestSpriteHitArea(t *testing.T) {
+	frameHitbox := &Hitbox{X: -5, Y: -10, Width: 10, Height: 10}
+	animation := &Animation{
+		Frames: []Frame{
+			{Image: new(ebiten.Image), Duration: 1, Hitbox: frameHitbox},
+			{Image: new(ebiten.Image), Duration: 1},
+		},
+	}
+	sprite := NewSprite(XCentre, YBottom).SetSize(40, 20).MoveTo(100, 50)
+
+	// image rectangle
+	assert.Equal(t, Rect{X: 80, Y: 30, Width: 40, Height: 20}, sprite.HitArea())
+
+	// hitbox of the sprite
+	sprite.SetHitbox(&Hitbox{Y: -10, Radius: 8})
+	assert.Equal(t, Circle{X: 100, Y: 40, Radius: 8}, sprite.HitArea())
+	assert.True(t, sprite.CollidePoint(100, 48))
+	assert.False(t, sprite.CollidePoint(81, 31))
+
+	// hitbox of the frame, then back to the hitbox of the sprite on the next frame
+	sprite.Play(animation)
+	assert.Equal(t, Rect{X: 95, Y: 40, Width: 10, Height: 10}, sprite.HitArea())
+	sprite.Update()
+	assert.Equal(t, Circle{X: 100, Y: 40, Radius: 8}, sprite.HitArea())
+}
+
+func TestSpriteCollides(t *testing.T) {
+	player := NewSprite(XCentre, YBottom).SetSize(60, 70).SetHitbox(&Hitbox{X: -20, Y: -60, Width: 40, Height: 60})
+	bolt := NewSprite(XCentre, YCentre).SetSize(40, 30).SetHitbox(&Hitbox{X: -5, Y: -5, Width: 10, Height: 10})
+	player.MoveTo(100, 100)
+
+	// the images overlap, but not the hitboxes
+	bolt.MoveTo(70, 70)
+	assert.False(t, player.Collides(bolt))
+	bolt.MoveTo(76, 70)
+	assert.True(t, player.Collides(bolt))
+	assert.True(t, bolt.Collides(player))
+}
+
+func TestSpriteTransforms(t *testing.T) {
+	testData := []struct {
+		name             string
+		xType            XType
+		yType            YType
+		pivotX, pivotY   float64
+		flipX, flipY     bool
+		scaleX, scaleY   float64
+		left, top        float64 // of the 20x10 image drawn at 100, 50
+		right, bottom    float64
+		imageX0, imageY0 float64 // position of the top left pixel of the image on the screen
+	}{
+		{"no transform", XLeft, YTop, 0, 0, false, false, 1, 1, 100, 50, 120, 60, 100, 50},
+		{"flip left top", XLeft, YTop, 0, 0, true, false, 1, 1, 80, 50, 100, 60, 100, 50},
+		{"flip centre bottom", XCentre, YBottom, 0, 0, true, true, 1, 1, 90, 50, 110, 60, 110, 60},
+		{"flip with pivot", XCentre, YBottom, 4, 0, true, false, 1, 1, 94, 40, 114, 50, 114, 40},
+		{"scale centre bottom", XCentre, YBottom, 0, 0, false, false, 2, 3, 80, 20, 120, 50, 80, 20},
+		{"scale with pivot", XLeft, YTop, 5, 5, false, false, 2, 2, 90, 40, 130, 60, 90, 40},
+		{"scale and flip", XRight, YCentre, 0, 0, true, false, 0.5, 1, 100, 45, 110, 55, 110, 45},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			sprite := NewSprite(testItem.xType, testItem.yType).
+				SetSize(20, 10).
+				SetPivot(testItem.pivotX, testItem.pivotY).
+				SetFlip(testItem.flipX, testItem.flipY).
+				SetScale(testItem.scaleX, testItem.scaleY).
+				MoveTo(100, 50)
+			assert.Equal(t, testItem.left, sprite.X(XLeft))
+			assert.Equal(t, testItem.top, sprite.Y(YTop))
+			assert.Equal(t, testItem.right, sprite.X(XRight))
+			assert.Equal(t, testItem.bottom, sprite.Y(YBottom))
+
+			geoM := sprite.geoM(20, 10)
+			x0, y0 := geoM.Apply(0, 0)
+			assert.InDelta(t, testItem.imageX0, x0, 0.001)
+			assert.InDelta(t, testItem.imageY0, y0, 0.001)
+			// the opposite corner
+			x1, y1 := geoM.Apply(20, 10)
+			assert.InDelta(t, testItem.left+testItem.right-testItem.imageX0, x1, 0.001)
+			assert.InDelta(t, testItem.top+testItem.bottom-testItem.imageY0, y1, 0.001)
+
+			// moving by type still moves that point of the image
+			sprite.MoveToType(200, 100, XLeft, YTop)
+			assert.Equal(t, 200.0, sprite.X(XLeft))
+			assert.Equal(t, 100.0, sprite.Y(YTop))
+		})
+	}
+}
+
+func TestSpriteRotation(t *testing.T) {
+	sprite := NewSprite(XCentre, YBottom).SetSize(20, 10).SetRotation(math.Pi/2).MoveTo(100, 50)
+	geoM := sprite.geoM(20, 10)
+	// the bottom centre stays at the position
+	x, y := geoM.Apply(10, 10)
+	assert.InDelta(t, 100, x, 0.001)
+	assert.InDelta(t, 50, y, 0.001)
+	// the top centre turns clockwise to the right of the position
+	x, y = geoM.Apply(10, 0)
+	assert.InDelta(t, 110, x, 0.001)
+	assert.InDelta(t, 50, y, 0.001)
+	// the coordinates don't change
+	assert.Equal(t, 90.0, sprite.X(XLeft))
+	assert.Equal(t, 40.0, sprite.Y(YTop))
+}
+
+func TestSpriteTransformedHitArea(t *testing.T) {
+	testData := []struct {
+		name           string
+		hitbox         Hitbox
+		flipX, flipY   bool
+		scaleX, scaleY float64
+		area           Shape
+	}{
+		{"rectangle", Hitbox{X: -4, Y: -10, Width: 6, Height: 10}, false, false, 1, 1, Rect{96, 40, 6, 10}},
+		{"flipped rectangle", Hitbox{X: -4, Y: -10, Width: 6, Height: 10}, true, false, 1, 1, Rect{98, 40, 6, 10}},
+		{"upside down rectangle", Hitbox{X: -4, Y: -10, Width: 6, Height: 10}, false, true, 1, 1, Rect{96, 50, 6, 10}},
+		{"scaled rectangle", Hitbox{X: -4, Y: -10, Width: 6, Height: 10}, false, false, 2, 0.5, Rect{92, 45, 12, 5}},
+		{"flipped circle", Hitbox{X: 3, Y: -5, Radius: 4}, true, false, 1, 1, Circle{97, 45, 4}},
+		{"scaled circle", Hitbox{X: 3, Y: -5, Radius: 4}, false, false, 2, 3, Circle{106, 35, 12}},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			hitbox := testItem.hitbox
+			sprite := NewSprite(XCentre, YBottom).
+				SetHitbox(&hitbox).
+				SetFlip(testItem.flipX, testItem.flipY).
+				SetScale(testItem.scaleX, testItem.scaleY).
+				MoveTo(100, 50)
+			assert.Equal(t, testItem.area, sprite.HitArea())
+		})
+	}
+}
+
+func TestSpriteAnimationFlip(t *testing.T) {
+	animation := &Animation{Frames: testFrames(1, 1), FlipX: true}
+	sprite := NewSprite(XLeft, YTop).SetSize(20, 10).MoveTo(100, 50).Play(animation)
+	assert.Equal(t, 80.0, sprite.X(XLeft))
+	// flipping the sprite flips the animation back
+	sprite.SetFlip(true, false)
+	assert.Equal(t, 100.0, sprite.X(XLeft))
+}
+
+func TestSpriteReloadedAnimation(t *testing.T) {
+	testData := []struct {
+		name   string
+		paused bool
+	}{
+		{"running", false},
+		{"paused", true},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			animation := &Animation{Frames: testFrames(4, 1), Loop: true}
+			sprite := NewSprite(XLeft, YTop).Play(animation)
+			framesPlayed(sprite, 3)
+			if testItem.paused {
+				sprite.Pause()
+			}
+
+			shorter := &Animation{Frames: testFrames(2, 1), Loop: true}
+			shorter.Frames[0].Hitbox = &Hitbox{Width: 4, Height: 4}
+			animation.Reload(shorter)
+			assert.Equal(t, 0, sprite.Frame())
+			assert.Equal(t, Rect{Width: 4, Height: 4}, sprite.HitArea())
+			assert.Same(t, shorter.Frames[0].Image, sprite.image)
+		})
+	}
+}
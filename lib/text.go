@@ -0,0 +1,203 @@
+package lib
+
+import (
+	"bytes"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+)
+
+// outlineSteps is the number of copies of the text drawn around it to make the outline
+const outlineSteps = 12
+
+// TextStyle describes how to draw a text with a TextRenderer
+type TextStyle struct {
+	Size          float64     // font size in pixels
+	Colour        color.Color // white when nil
+	Align         XType       // the x coordinate is the left, centre or right of the text
+	LineSpacing   float64     // distance between lines, 1.2 x size when zero
+	Outline       float64     // outline width in pixels, no outline when zero
+	OutlineColour color.Color // black when nil
+	Shadow        float64     // drop shadow offset in pixels, no shadow when zero
+	ShadowColour  color.Color // black when nil
+}
+
+// TextRenderer draws any unicode text with a TrueType font, at any size.
+//
+// Characters missing from the font are taken from the fallback fonts, in the order they were added.
+// When a text still contains characters missing from all of them, the whole text is drawn
+// with the fixed size fallback face instead, scaled to the size of the style.
+type TextRenderer struct {
+	sources   []*text.GoTextFaceSource
+	fonts     []*sfnt.Font // same fonts as the sources, to check which characters are available
+	buffer    sfnt.Buffer
+	fixed     text.Face
+	fixedSize float64
+	faces     map[float64]text.Face
+	op        *text.DrawOptions
+}
+
+// NewTextRenderer loads the TrueType (or OpenType) font
+func NewTextRenderer(fontFile io.Reader) (*TextRenderer, error) {
+	r := &TextRenderer{
+		sources: make([]*text.GoTextFaceSource, 0, 2),
+		fonts:   make([]*sfnt.Font, 0, 2),
+		faces:   make(map[float64]text.Face, 4),
+		op:      &text.DrawOptions{},
+	}
+	err := r.AddFallback(fontFile)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// AddFallback loads a TrueType (or OpenType) font used for the characters missing from the previous fonts
+func (r *TextRenderer) AddFallback(fontFile io.Reader) error {
+	data, err := io.ReadAll(fontFile)
+	if err != nil {
+		return err
+	}
+	parsed, err := sfnt.Parse(data)
+	if err != nil {
+		return err
+	}
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	r.sources = append(r.sources, source)
+	r.fonts = append(r.fonts, parsed)
+	// the faces need to be created again with the new font
+	r.faces = make(map[float64]text.Face, 4)
+	return nil
+}
+
+// SetFixedFallback sets a fixed size face (like a bitmap font) used to draw the texts with characters
+// missing from all the other fonts
+func (r *TextRenderer) SetFixedFallback(face font.Face) {
+	r.fixed = text.NewGoXFace(face)
+	r.fixedSize = float64(face.Metrics().Height) / (1 << 6)
+}
+
+// Covers returns true when all the characters of the text are available in the fonts (excluding the fixed fallback)
+func (r *TextRenderer) Covers(str string) bool {
+	for _, char := range str {
+		if char == '\n' || r.hasGlyph(char) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// Measure returns the size of the text in pixels
+func (r *TextRenderer) Measure(str string, style *TextStyle) (width, height float64) {
+	if r.useFixed(str) {
+		scale := style.Size / r.fixedSize
+		width, height = text.Measure(str, r.fixed, lineSpacing(style)/scale)
+		return width * scale, height * scale
+	}
+	return text.Measure(str, r.face(style.Size), lineSpacing(style))
+}
+
+// Draw the text at the x and y coordinates (y being the top of the text)
+func (r *TextRenderer) Draw(screen *ebiten.Image, str string, x, y float64, style *TextStyle) {
+	if style.Shadow != 0 {
+		r.draw(screen, str, x+style.Shadow, y+style.Shadow, style, colourOrBlack(style.ShadowColour))
+	}
+	if style.Outline != 0 {
+		outlineColour := colourOrBlack(style.OutlineColour)
+		for i := 0; i < outlineSteps; i++ {
+			angle := 2 * math.Pi * float64(i) / outlineSteps
+			r.draw(screen, str, x+math.Cos(angle)*style.Outline, y+math.Sin(angle)*style.Outline, style, outlineColour)
+		}
+	}
+	colour := style.Colour
+	if colour == nil {
+		colour = color.White
+	}
+	r.draw(screen, str, x, y, style, colour)
+}
+
+func (r *TextRenderer) draw(screen *ebiten.Image, str string, x, y float64, style *TextStyle, colour color.Color) {
+	face := r.face(style.Size)
+	r.op.GeoM.Reset()
+	r.op.LineSpacing = lineSpacing(style)
+	if r.useFixed(str) {
+		scale := style.Size / r.fixedSize
+		face = r.fixed
+		r.op.GeoM.Scale(scale, scale)
+		r.op.LineSpacing /= scale
+	}
+	r.op.GeoM.Translate(x, y)
+	r.op.ColorScale.Reset()
+	r.op.ColorScale.ScaleWithColor(colour)
+	switch style.Align {
+	case XCentre:
+		r.op.PrimaryAlign = text.AlignCenter
+	case XRight:
+		r.op.PrimaryAlign = text.AlignEnd
+	default:
+		r.op.PrimaryAlign = text.AlignStart
+	}
+	text.Draw(screen, str, face, r.op)
+}
+
+// face returns the font face of that size, creating it the first time
+func (r *TextRenderer) face(size float64) text.Face {
+	if face, found := r.faces[size]; found {
+		return face
+	}
+	var face text.Face = &text.GoTextFace{
+		Source: r.sources[0],
+		Size:   size,
+	}
+	if len(r.sources) > 1 {
+		faces := make([]text.Face, len(r.sources))
+		for i, source := range r.sources {
+			faces[i] = &text.GoTextFace{
+				Source: source,
+				Size:   size,
+			}
+		}
+		// the faces share the same (default) direction: this cannot fail
+		face, _ = text.NewMultiFace(faces...)
+	}
+	r.faces[size] = face
+	return face
+}
+
+// useFixed returns true when the text needs to be drawn with the fixed size fallback
+func (r *TextRenderer) useFixed(str string) bool {
+	return r.fixed != nil && !r.Covers(str)
+}
+
+func (r *TextRenderer) hasGlyph(char rune) bool {
+	for _, f := range r.fonts {
+		index, err := f.GlyphIndex(&r.buffer, char)
+		if err == nil && index != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func lineSpacing(style *TextStyle) float64 {
+	if style.LineSpacing == 0 {
+		return style.Size * 1.2
+	}
+	return style.LineSpacing
+}
+
+func colourOrBlack(colour color.Color) color.Color {
+	if colour == nil {
+		return color.Black
+	}
+	return colour
+}
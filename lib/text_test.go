@@ -0,0 +1,44 @@
+package lib
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+)
+
+func TestTextCovers(t *testing.T) {
+	renderer, err := NewTextRenderer(bytes.NewReader(gomono.TTF))
+	require.NoError(t, err)
+
+	assert.True(t, renderer.Covers("LEVEL 1"))
+	assert.True(t, renderer.Covers("NIVEAU 1\nDIFFICULTÉ"))
+	assert.False(t, renderer.Covers("レベル 1"))
+
+	err = renderer.AddFallback(bytes.NewReader(gobold.TTF))
+	require.NoError(t, err)
+	assert.False(t, renderer.Covers("レベル 1"))
+}
+
+func TestTextInvalidFont(t *testing.T) {
+	_, err := NewTextRenderer(bytes.NewReader([]byte("not a font")))
+	assert.Error(t, err)
+}
+
+func TestTextMeasureLineSpacing(t *testing.T) {
+	renderer, err := NewTextRenderer(bytes.NewReader(gomono.TTF))
+	require.NoError(t, err)
+	style := &TextStyle{Size: 20}
+
+	width, height := renderer.Measure("AB", style)
+	assert.Greater(t, width, 0.0)
+	_, twoLines := renderer.Measure("AB\nCD", style)
+	assert.InDelta(t, 24.0, twoLines-height, 0.001) // 1.2 x size by default
+
+	style.LineSpacing = 30
+	_, twoLines = renderer.Measure("AB\nCD", style)
+	assert.InDelta(t, 30.0, twoLines-height, 0.001)
+}
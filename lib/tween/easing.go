@@ -0,0 +1,104 @@
+package tween
+
+import "math"
+
+// Easing maps the progress of a tween (from 0 to 1) to the progress of the value.
+// It returns 0 at the start and 1 at the end, but can go outside of this range in between (like BackOut).
+type Easing func(t float64) float64
+
+// Linear progresses at a constant speed
+func Linear(t float64) float64 {
+	return t
+}
+
+// QuadIn starts slowly and accelerates
+func QuadIn(t float64) float64 {
+	return t * t
+}
+
+// QuadOut starts quickly and decelerates
+func QuadOut(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// QuadInOut accelerates then decelerates
+func QuadInOut(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 2)/2
+}
+
+// CubicIn starts slowly and accelerates, more than QuadIn
+func CubicIn(t float64) float64 {
+	return t * t * t
+}
+
+// CubicOut starts quickly and decelerates, more than QuadOut
+func CubicOut(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+// CubicInOut accelerates then decelerates, more than QuadInOut
+func CubicInOut(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+// SineIn follows a quarter of a sine wave, starting slowly
+func SineIn(t float64) float64 {
+	return 1 - math.Cos(t*math.Pi/2)
+}
+
+// SineOut follows a quarter of a sine wave, ending slowly
+func SineOut(t float64) float64 {
+	return math.Sin(t * math.Pi / 2)
+}
+
+// SineInOut follows half a sine wave
+func SineInOut(t float64) float64 {
+	return -(math.Cos(math.Pi*t) - 1) / 2
+}
+
+const (
+	backOvershoot = 1.70158
+	backFactor    = backOvershoot + 1
+)
+
+// BackIn moves slightly backward before going forward
+func BackIn(t float64) float64 {
+	return backFactor*t*t*t - backOvershoot*t*t
+}
+
+// BackOut goes slightly past the end before coming back to it
+func BackOut(t float64) float64 {
+	return 1 + backFactor*math.Pow(t-1, 3) + backOvershoot*math.Pow(t-1, 2)
+}
+
+// ElasticOut overshoots the end and oscillates around it like a spring
+func ElasticOut(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*(2*math.Pi/3)) + 1
+}
+
+// BounceOut bounces on the end value like a falling ball
+func BounceOut(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
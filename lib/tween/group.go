@@ -0,0 +1,75 @@
+package tween
+
+// Group plays tweens one after the other (sequence) or all at the same time (parallel)
+type Group struct {
+	tweens     []Tweener
+	parallel   bool
+	current    int // tween played in a sequence
+	onComplete func()
+	finished   bool
+}
+
+// Sequence creates a group playing the tweens one after the other: each tween starts on the tick after the previous one finished
+func Sequence(tweens ...Tweener) *Group {
+	return &Group{
+		tweens: tweens,
+	}
+}
+
+// Parallel creates a group playing all the tweens at the same time. It finishes with the longest tween.
+func Parallel(tweens ...Tweener) *Group {
+	return &Group{
+		tweens:   tweens,
+		parallel: true,
+	}
+}
+
+// OnComplete registers a function called on the tick the whole group finishes
+func (g *Group) OnComplete(callback func()) *Group {
+	g.onComplete = callback
+	return g
+}
+
+// Update implements Tweener
+func (g *Group) Update() bool {
+	if g.finished {
+		return true
+	}
+	if g.parallel {
+		finished := true
+		for _, tween := range g.tweens {
+			if !tween.Update() {
+				finished = false
+			}
+		}
+		if !finished {
+			return false
+		}
+	} else {
+		if g.current < len(g.tweens) && g.tweens[g.current].Update() {
+			g.current++
+		}
+		if g.current < len(g.tweens) {
+			return false
+		}
+	}
+	g.finished = true
+	if g.onComplete != nil {
+		g.onComplete()
+	}
+	return true
+}
+
+// Finished implements Tweener
+func (g *Group) Finished() bool {
+	return g.finished
+}
+
+// Reset implements Tweener
+func (g *Group) Reset() {
+	for _, tween := range g.tweens {
+		tween.Reset()
+	}
+	g.current = 0
+	g.finished = false
+}
@@ -0,0 +1,153 @@
+// Package tween changes values over a number of game ticks, following easing curves.
+//
+// Tweens are driven by the game: call Update once per tick until it returns true.
+//
+//	slide := tween.Float(&y, 500, 450, 40, tween.BackOut).Delay(20)
+//	...
+//	slide.Update()
+package tween
+
+// Forever repeats a tween until it's reset
+const Forever = -1
+
+// Tweener is a tween or a group of tweens
+type Tweener interface {
+	// Update moves forward by one tick. It returns true when finished.
+	Update() bool
+	// Finished returns true when all the ticks have been played
+	Finished() bool
+	// Reset goes back to the beginning (the values are only changed on the next Update)
+	Reset()
+}
+
+// Tween changes a value from one number to another over a number of ticks
+type Tween struct {
+	set        func(float64) // nil to only wait
+	from       float64
+	to         float64
+	duration   int
+	easing     Easing
+	delay      int // ticks before starting
+	yoyo       bool
+	repeat     int // number of times the tween is played again, or Forever
+	onComplete func()
+	waited     int // ticks of delay already waited
+	elapsed    int // ticks since the start of the current cycle
+	cycles     int // number of cycles finished
+	finished   bool
+}
+
+// New creates a tween calling set on each tick with a value from "from" to "to".
+// The value is "to" on the last tick of the duration.
+func New(set func(float64), from, to float64, duration int, easing Easing) *Tween {
+	if easing == nil {
+		easing = Linear
+	}
+	return &Tween{
+		set:      set,
+		from:     from,
+		to:       to,
+		duration: duration,
+		easing:   easing,
+	}
+}
+
+// Float creates a tween changing the value
+func Float(value *float64, from, to float64, duration int, easing Easing) *Tween {
+	return New(func(v float64) {
+		*value = v
+	}, from, to, duration, easing)
+}
+
+// Wait creates a tween doing nothing for a number of ticks, to pause a sequence
+func Wait(ticks int) *Tween {
+	return New(nil, 0, 0, ticks, Linear)
+}
+
+// Delay waits for a number of ticks before starting. The delay is not repeated.
+func (t *Tween) Delay(ticks int) *Tween {
+	t.delay = ticks
+	return t
+}
+
+// Yoyo goes back to the start value after reaching the end value, doubling the duration
+func (t *Tween) Yoyo() *Tween {
+	t.yoyo = true
+	return t
+}
+
+// Repeat plays the tween again a number of times (or Forever) after the first time
+func (t *Tween) Repeat(times int) *Tween {
+	t.repeat = times
+	return t
+}
+
+// OnComplete registers a function called on the tick the tween finishes
+func (t *Tween) OnComplete(callback func()) *Tween {
+	t.onComplete = callback
+	return t
+}
+
+// Update implements Tweener
+func (t *Tween) Update() bool {
+	if t.finished {
+		return true
+	}
+	if t.waited < t.delay {
+		t.waited++
+		return false
+	}
+	length := t.duration
+	if t.yoyo {
+		length *= 2
+	}
+	t.elapsed++
+	if t.elapsed > length {
+		t.elapsed = length
+	}
+	if t.set != nil {
+		t.set(t.Value())
+	}
+	if t.elapsed < length {
+		return false
+	}
+	t.cycles++
+	if t.repeat == Forever || t.cycles <= t.repeat {
+		t.elapsed = 0
+		return false
+	}
+	t.finished = true
+	if t.onComplete != nil {
+		t.onComplete()
+	}
+	return true
+}
+
+// Value returns the value of the current tick
+func (t *Tween) Value() float64 {
+	if t.duration <= 0 {
+		if t.yoyo {
+			return t.from
+		}
+		return t.to
+	}
+	elapsed := t.elapsed
+	if elapsed > t.duration {
+		// going back
+		elapsed = 2*t.duration - elapsed
+	}
+	return t.from + (t.to-t.from)*t.easing(float64(elapsed)/float64(t.duration))
+}
+
+// Finished implements Tweener
+func (t *Tween) Finished() bool {
+	return t.finished
+}
+
+// Reset implements Tweener
+func (t *Tween) Reset() {
+	t.waited = 0
+	t.elapsed = 0
+	t.cycles = 0
+	t.finished = false
+}
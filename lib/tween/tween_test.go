@@ -0,0 +1,185 @@
+package tween
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// play updates the tweener a number of times and returns the value after each update
+func play(tweener Tweener, value *float64, updates int) []float64 {
+	values := make([]float64, updates)
+	for i := range values {
+		tweener.Update()
+		values[i] = *value
+	}
+	return values
+}
+
+func TestTween(t *testing.T) {
+	testData := []struct {
+		name     string
+		tween    func(value *float64) *Tween
+		values   []float64
+		finished bool
+	}{
+		{
+			name:     "linear",
+			tween:    func(value *float64) *Tween { return Float(value, 0, 10, 4, Linear) },
+			values:   []float64{2.5, 5, 7.5, 10, 10},
+			finished: true,
+		},
+		{
+			name:     "quad in",
+			tween:    func(value *float64) *Tween { return Float(value, 0, 16, 4, QuadIn) },
+			values:   []float64{1, 4, 9, 16},
+			finished: true,
+		},
+		{
+			name:     "delay",
+			tween:    func(value *float64) *Tween { return Float(value, 10, 20, 2, nil).Delay(2) },
+			values:   []float64{-1, -1, 15, 20},
+			finished: true,
+		},
+		{
+			name:     "yoyo",
+			tween:    func(value *float64) *Tween { return Float(value, 0, 10, 2, Linear).Yoyo() },
+			values:   []float64{5, 10, 5, 0, 0},
+			finished: true,
+		},
+		{
+			name:     "repeat",
+			tween:    func(value *float64) *Tween { return Float(value, 0, 10, 2, Linear).Repeat(1) },
+			values:   []float64{5, 10, 5, 10, 10},
+			finished: true,
+		},
+		{
+			name:   "yoyo forever",
+			tween:  func(value *float64) *Tween { return Float(value, 0, 10, 1, Linear).Yoyo().Repeat(Forever) },
+			values: []float64{10, 0, 10, 0, 10, 0, 10},
+		},
+		{
+			name:     "no duration",
+			tween:    func(value *float64) *Tween { return Float(value, 0, 10, 0, Linear) },
+			values:   []float64{10, 10},
+			finished: true,
+		},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			value := -1.0
+			tween := testItem.tween(&value)
+			assert.Equal(t, testItem.values, play(tween, &value, len(testItem.values)))
+			assert.Equal(t, testItem.finished, tween.Finished())
+		})
+	}
+}
+
+func TestTweenOnComplete(t *testing.T) {
+	completed := 0
+	value := 0.0
+	tween := Float(&value, 0, 1, 2, Linear).OnComplete(func() {
+		completed++
+	})
+	assert.False(t, tween.Update())
+	assert.Equal(t, 0, completed)
+	assert.True(t, tween.Update())
+	assert.Equal(t, 1, completed)
+	// only called once
+	assert.True(t, tween.Update())
+	assert.Equal(t, 1, completed)
+
+	// again after a reset
+	tween.Reset()
+	assert.False(t, tween.Finished())
+	play(tween, &value, 2)
+	assert.Equal(t, 2, completed)
+}
+
+func TestSequence(t *testing.T) {
+	value := 0.0
+	completed := false
+	sequence := Sequence(
+		Float(&value, 0, 10, 2, Linear),
+		Wait(2),
+		Float(&value, 10, 0, 1, Linear),
+	).OnComplete(func() {
+		completed = true
+	})
+	assert.Equal(t, []float64{5, 10, 10, 10}, play(sequence, &value, 4))
+	assert.False(t, sequence.Finished())
+	assert.False(t, completed)
+	assert.Equal(t, []float64{0, 0}, play(sequence, &value, 2))
+	assert.True(t, sequence.Finished())
+	assert.True(t, completed)
+
+	sequence.Reset()
+	assert.False(t, sequence.Finished())
+	assert.Equal(t, []float64{5, 10}, play(sequence, &value, 2))
+}
+
+func TestParallel(t *testing.T) {
+	x, y := 0.0, 0.0
+	completed := 0
+	parallel := Parallel(
+		Float(&x, 0, 10, 2, Linear),
+		Float(&y, 0, 30, 3, Linear),
+	).OnComplete(func() {
+		completed++
+	})
+	assert.False(t, parallel.Update())
+	assert.Equal(t, [2]float64{5, 10}, [2]float64{x, y})
+	assert.False(t, parallel.Update())
+	assert.Equal(t, [2]float64{10, 20}, [2]float64{x, y})
+	assert.True(t, parallel.Update())
+	assert.Equal(t, [2]float64{10, 30}, [2]float64{x, y})
+	assert.True(t, parallel.Finished())
+	assert.Equal(t, 1, completed)
+}
+
+func TestNestedGroups(t *testing.T) {
+	x, y := 0.0, 0.0
+	group := Sequence(
+		Parallel(Float(&x, 0, 2, 2, Linear), Float(&y, 0, 1, 1, Linear)),
+		Float(&y, 1, 0, 2, Linear).Delay(1),
+	)
+	values := make([][2]float64, 0)
+	for !group.Update() {
+		values = append(values, [2]float64{x, y})
+	}
+	values = append(values, [2]float64{x, y})
+	assert.Equal(t, [][2]float64{{1, 1}, {2, 1}, {2, 1}, {2, 0.5}, {2, 0}}, values)
+}
+
+func TestEasings(t *testing.T) {
+	easings := map[string]Easing{
+		"Linear":     Linear,
+		"QuadIn":     QuadIn,
+		"QuadOut":    QuadOut,
+		"QuadInOut":  QuadInOut,
+		"CubicIn":    CubicIn,
+		"CubicOut":   CubicOut,
+		"CubicInOut": CubicInOut,
+		"SineIn":     SineIn,
+		"SineOut":    SineOut,
+		"SineInOut":  SineInOut,
+		"BackIn":     BackIn,
+		"BackOut":    BackOut,
+		"ElasticOut": ElasticOut,
+		"BounceOut":  BounceOut,
+	}
+	for name, easing := range easings {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, 0, easing(0), 1e-9)
+			assert.InDelta(t, 1, easing(1), 1e-9)
+		})
+	}
+	// symmetric curves are half way in the middle
+	for _, easing := range []Easing{Linear, QuadInOut, CubicInOut, SineInOut} {
+		assert.InDelta(t, 0.5, easing(0.5), 1e-9)
+	}
+	// overshooting curves
+	assert.Less(t, BackIn(0.2), 0.0)
+	assert.Greater(t, BackOut(0.8), 1.0)
+	assert.Greater(t, ElasticOut(0.2), 1.0)
+}
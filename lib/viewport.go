@@ -0,0 +1,117 @@
+package lib
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ScaleMode is how the game screen is scaled to the window
+type ScaleMode int
+
+// ScaleMode
+const (
+	ScaleSmooth       ScaleMode = iota // fills the window, with linear filtering
+	ScalePixelPerfect                  // largest whole number scale, with nearest filtering
+)
+
+// String representation of ScaleMode
+func (m ScaleMode) String() string {
+	switch m {
+	case ScalePixelPerfect:
+		return "pixel-perfect"
+	default:
+		return "smooth"
+	}
+}
+
+// Viewport draws a game screen of a fixed size in the centre of the window, keeping its aspect ratio.
+// The borders around it (letterbox) are filled with the border colour.
+type Viewport struct {
+	width   int
+	height  int
+	mode    ScaleMode
+	border  color.Color
+	scale   float64
+	offsetX float64
+	offsetY float64
+	op      *ebiten.DrawImageOptions
+}
+
+// NewViewport creates a viewport for a game screen of this size
+func NewViewport(width, height int) *Viewport {
+	return &Viewport{
+		width:  width,
+		height: height,
+		border: color.Black,
+		scale:  1,
+		op:     &ebiten.DrawImageOptions{},
+	}
+}
+
+// SetMode sets how the game screen is scaled. Call Resize afterwards.
+func (v *Viewport) SetMode(mode ScaleMode) *Viewport {
+	v.mode = mode
+	return v
+}
+
+// Mode returns how the game screen is scaled
+func (v *Viewport) Mode() ScaleMode {
+	return v.mode
+}
+
+// SetBorder sets the colour of the letterbox
+func (v *Viewport) SetBorder(border color.Color) *Viewport {
+	v.border = border
+	return v
+}
+
+// Resize fits the game screen in a window of this size (in pixels)
+func (v *Viewport) Resize(windowWidth, windowHeight int) {
+	scale := math.Min(float64(windowWidth)/float64(v.width), float64(windowHeight)/float64(v.height))
+	if v.mode == ScalePixelPerfect && scale >= 1 {
+		// a window smaller than the game screen still shows all of it
+		scale = math.Floor(scale)
+	}
+	if scale <= 0 {
+		scale = 1
+	}
+	v.scale = scale
+	v.offsetX = math.Floor((float64(windowWidth) - float64(v.width)*scale) / 2)
+	v.offsetY = math.Floor((float64(windowHeight) - float64(v.height)*scale) / 2)
+}
+
+// Scale returns the scale of the game screen in the window
+func (v *Viewport) Scale() float64 {
+	return v.scale
+}
+
+// Offset returns the position of the top left corner of the game screen in the window
+func (v *Viewport) Offset() (float64, float64) {
+	return v.offsetX, v.offsetY
+}
+
+// ToGame converts window coordinates (like the cursor position) to game screen coordinates.
+// The coordinates are outside of the game screen over the letterbox.
+func (v *Viewport) ToGame(x, y float64) (float64, float64) {
+	return (x - v.offsetX) / v.scale, (y - v.offsetY) / v.scale
+}
+
+// ToWindow converts game screen coordinates to window coordinates
+func (v *Viewport) ToWindow(x, y float64) (float64, float64) {
+	return x*v.scale + v.offsetX, y*v.scale + v.offsetY
+}
+
+// Draw the game screen in the window
+func (v *Viewport) Draw(window, game *ebiten.Image) {
+	window.Fill(v.border)
+	v.op.GeoM.Reset()
+	v.op.GeoM.Scale(v.scale, v.scale)
+	v.op.GeoM.Translate(v.offsetX, v.offsetY)
+	v.op.Filter = ebiten.FilterLinear
+	if v.mode == ScalePixelPerfect || v.scale == math.Floor(v.scale) {
+		v.op.Filter = ebiten.FilterNearest
+	}
+	window.DrawImage(game, v.op)
+}
@@ -0,0 +1,54 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewportResize(t *testing.T) {
+	testData := []struct {
+		name    string
+		mode    ScaleMode
+		window  [2]int
+		scale   float64
+		offsetX float64
+		offsetY float64
+	}{
+		{"same size", ScaleSmooth, [2]int{800, 480}, 1, 0, 0},
+		{"same size pixel perfect", ScalePixelPerfect, [2]int{800, 480}, 1, 0, 0},
+		{"double", ScaleSmooth, [2]int{1600, 960}, 2, 0, 0},
+		{"wider smooth", ScaleSmooth, [2]int{1920, 1080}, 2.25, 60, 0},
+		{"wider pixel perfect", ScalePixelPerfect, [2]int{1920, 1080}, 2, 160, 60},
+		{"taller smooth", ScaleSmooth, [2]int{800, 800}, 1, 0, 160},
+		{"taller pixel perfect", ScalePixelPerfect, [2]int{1000, 1000}, 1, 100, 260},
+		{"smaller pixel perfect", ScalePixelPerfect, [2]int{400, 480}, 0.5, 0, 120},
+		{"no window", ScalePixelPerfect, [2]int{0, 0}, 1, -400, -240},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			viewport := NewViewport(800, 480).SetMode(testItem.mode)
+			viewport.Resize(testItem.window[0], testItem.window[1])
+			assert.Equal(t, testItem.scale, viewport.Scale())
+			offsetX, offsetY := viewport.Offset()
+			assert.Equal(t, testItem.offsetX, offsetX)
+			assert.Equal(t, testItem.offsetY, offsetY)
+		})
+	}
+}
+
+func TestViewportCoordinates(t *testing.T) {
+	viewport := NewViewport(800, 480).SetMode(ScalePixelPerfect)
+	viewport.Resize(1920, 1080)
+
+	x, y := viewport.ToGame(160, 60)
+	assert.Equal(t, [2]float64{0, 0}, [2]float64{x, y})
+	x, y = viewport.ToGame(960, 540)
+	assert.Equal(t, [2]float64{400, 240}, [2]float64{x, y})
+	// over the letterbox
+	x, y = viewport.ToGame(0, 0)
+	assert.Equal(t, [2]float64{-80, -30}, [2]float64{x, y})
+
+	x, y = viewport.ToWindow(400, 240)
+	assert.Equal(t, [2]float64{960, 540}, [2]float64{x, y})
+}
@@ -21,3 +21,15 @@ func (x XType) String() string {
 		return "X left"
 	}
 }
+
+// offset returns the distance from the left of an image to the coordinate type
+func (x XType) offset(width float64) float64 {
+	switch x {
+	case XCentre:
+		return width / 2
+	case XRight:
+		return width
+	default:
+		return 0
+	}
+}
@@ -21,3 +21,15 @@ func (y YType) String() string {
 		return "Y top"
 	}
 }
+
+// offset returns the distance from the top of an image to the coordinate type
+func (y YType) offset(height float64) float64 {
+	switch y {
+	case YCentre:
+		return height / 2
+	case YBottom:
+		return height
+	default:
+		return 0
+	}
+}
@@ -1,52 +1,113 @@
 package main
 
 import (
+	"flag"
 	_ "image/png"
+	"io"
 	"log"
 
+	"github.com/creativeprojects/cavern/i18n"
+	"github.com/creativeprojects/cavern/lib"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 )
 
-// Images
+// Resources not generated by go generate (see assets_gen.go)
 const (
-	imageTitle       = "title"
-	imagePlayerBlank = "blank"
-	imagePlayerStill = "still"
-	imageJumpLeft    = "jump0"
-	imageJumpRight   = "jump1"
-	imageBlowLeft    = "blow0"
-	imageBlowRight   = "blow1"
-	imageRecoilLeft  = "recoil0"
-	imageRecoilRight = "recoil1"
-	imageLife        = "life"
-	imageHealth      = "health"
-	imagePlus        = "plus"
-	imageOver        = "over"
-
-	soundLevel = "level0"
-	soundJump  = "jump0"
-	soundScore = "score0"
-	soundBonus = "bonus0"
-	soundLife  = "life0"
-	soundOver  = "over0"
+	fontSheet           = "fonts/game.png"
+	fontMetrics         = "fonts/game.txt"
+	fontFallbackSheet   = "fonts/fallback.png"
+	fontFallbackMetrics = "fonts/fallback.txt"
+
+	defaultLanguage = "en"
+
+	// music tracks are optional: the default track is played instead when missing
+	musicDefault   = MusicTheme
+	musicTitle     = "title"
+	musicGame      = MusicTheme
+	musicHurry     = "hurry"
+	musicOver      = "over"
+	musicHighScore = "highscore"
 )
 
 var (
-	images map[string]*ebiten.Image
-	sounds map[string][]byte
+	atlas        *lib.Atlas
+	images       map[string]*ebiten.Image // images packed in the atlas
+	animations   map[string]*lib.Animation
+	shaders      map[string]*ebiten.Shader
+	themes       map[string]*Theme
+	sounds       map[string][]byte
+	bitmapFont   *lib.BitmapFont
+	textRenderer *lib.TextRenderer
+	messages     *i18n.Catalogue
 )
 
 func main() {
 	var err error
+	var packName string
+
+	flag.StringVar(&packName, "pack", "", "resource pack (directory or zip file) replacing the built-in images, sounds, music, fonts and languages")
+	flag.Parse()
 
 	if Debug {
 		log.SetFlags(log.LstdFlags | log.Lshortfile)
 	} else {
-		log.SetOutput(nil)
+		log.SetOutput(io.Discard)
+	}
+
+	if packName != "" {
+		pack, err := loadResourcePack(packName)
+		if err != nil {
+			log.Fatalf("cannot load resource pack: %v", err)
+		}
+		defer pack.Close()
+		log.Printf("using resource pack %q", packName)
+	}
+
+	settings, err := LoadSettings()
+	if err != nil {
+		log.Printf("cannot load settings: %v", err)
+	}
+
+	atlas, err = loadImages()
+	if err != nil {
+		log.Fatal(err)
+	}
+	images = atlas.Images()
+
+	animations, err = loadAnimations(assetFiles)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	shaders, err = loadShaders(assetFiles)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	themes, err = loadThemes(assetFiles, func(name string) *ebiten.Image {
+		return images[name]
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bitmapFont, err = loadFont()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	textRenderer, err = loadTextRenderer()
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	images, err = loadImages()
+	messages, err = loadMessages(settings.Language)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	LevelsDefinition, err = loadLevels(assetFiles)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -58,10 +119,17 @@ func main() {
 		log.Fatal(err)
 	}
 
+	err = validateAssets()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	ebiten.SetRunnableOnUnfocused(true)
-	ebiten.SetWindowSize(WindowWidth, WindowHeight)
+	ebiten.SetWindowSize(WindowWidth*settings.WindowScale, WindowHeight*settings.WindowScale)
 	ebiten.SetWindowTitle(WindowTitle)
-	game, err := NewGame(audioContext)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetFullscreen(settings.Fullscreen)
+	game, err := NewGame(audioContext, settings)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -69,3 +137,8 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// T returns the message translated in the current language
+func T(key string, args ...interface{}) string {
+	return messages.T(key, args...)
+}
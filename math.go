@@ -7,6 +7,17 @@ func min(value1, value2 int) int {
 	return value2
 }
 
+// clamp returns value limited to the range low to high (both included)
+func clamp(value, low, high int) int {
+	if value < low {
+		return low
+	}
+	if value > high {
+		return high
+	}
+	return value
+}
+
 func abs(value int) int {
 	if value < 0 {
 		return -value
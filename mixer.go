@@ -0,0 +1,305 @@
+package main
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// voice is a sound effect being played
+type voice struct {
+	player *audio.Player
+	sound  *byte   // identifies which sound is playing (first byte of the sound data)
+	gain   float64 // volume of this sound relative to the effects bus
+}
+
+// Mixer plays the music and the sound effects on two separate buses.
+// The number of sound effects playing at the same time is limited: when the limit is reached,
+// the oldest sound effect is stopped to make room for the new one.
+// Changing the music track crossfades the previous track into the new one.
+type Mixer struct {
+	audioContext  *audio.Context
+	tracks        map[string]*AudioPlayer // music tracks already loaded
+	music         *AudioPlayer            // music track playing (or fading in)
+	fadingOut     *AudioPlayer            // previous music track fading out, nil when none
+	fade          int                     // ticks since the crossfade started
+	duck          int                     // ticks left before the music goes back to its normal volume
+	musicVolume   float64
+	effectsVolume float64
+	voices        []*voice // sound effects currently playing, the oldest first
+	muted         bool
+	paused        bool
+}
+
+// NewMixer creates a new mixer and starts playing the music track
+func NewMixer(audioContext *audio.Context, track string, musicVolume, effectsVolume float64) (*Mixer, error) {
+	m := &Mixer{
+		audioContext:  audioContext,
+		tracks:        make(map[string]*AudioPlayer, 5),
+		musicVolume:   musicVolume,
+		effectsVolume: effectsVolume,
+		voices:        make([]*voice, 0, MaxVoices),
+	}
+	music, err := m.loadTrack(track)
+	if err != nil {
+		return nil, err
+	}
+	m.music = music
+	m.updateMusicVolume()
+	m.music.Play()
+	return m, nil
+}
+
+// PlayMusic crossfades the current music into the new track. It does nothing if the track is already playing
+func (m *Mixer) PlayMusic(track string) error {
+	player, err := m.loadTrack(track)
+	if err != nil {
+		return err
+	}
+	if player == m.music {
+		return nil
+	}
+	if m.fadingOut != nil && m.fadingOut != player {
+		// a crossfade was already running
+		m.fadingOut.Pause()
+	}
+	if m.fadingOut != player {
+		// start the track from the beginning, unless it's coming back before the end of its fade out
+		err = player.Rewind()
+		if err != nil {
+			return err
+		}
+	}
+	m.fadingOut = m.music
+	m.music = player
+	m.fade = 0
+	m.updateMusicVolume()
+	if !m.paused {
+		m.music.Play()
+	}
+	return nil
+}
+
+// MusicTrack returns the name of the music track playing
+func (m *Mixer) MusicTrack() string {
+	return m.music.Track()
+}
+
+// Duck lowers the volume of the music while the sound effect is playing
+func (m *Mixer) Duck(se []byte) {
+	ticks := len(se) * GameNormalSpeed / (SampleRate * 4)
+	if ticks > m.duck {
+		m.duck = ticks
+	}
+	m.updateMusicVolume()
+}
+
+// SetMusicVolume changes the volume of the music bus (between 0 and 1)
+func (m *Mixer) SetMusicVolume(volume float64) {
+	m.musicVolume = volume
+	m.updateMusicVolume()
+}
+
+// SetEffectsVolume changes the volume of the sound effects bus (between 0 and 1)
+func (m *Mixer) SetEffectsVolume(volume float64) {
+	m.effectsVolume = volume
+	for _, v := range m.voices {
+		v.player.SetVolume(m.volume(m.effectsVolume) * v.gain)
+	}
+}
+
+// ToggleMute mutes or un-mutes both the music and the sound effects
+func (m *Mixer) ToggleMute() {
+	m.muted = !m.muted
+	m.SetMusicVolume(m.musicVolume)
+	m.SetEffectsVolume(m.effectsVolume)
+}
+
+// IsMuted returns true when the mixer is muted
+func (m *Mixer) IsMuted() bool {
+	return m.muted
+}
+
+// SetPaused pauses or resumes the music and all the sound effects currently playing
+func (m *Mixer) SetPaused(paused bool) {
+	if m.paused == paused {
+		return
+	}
+	m.paused = paused
+	for _, music := range []*AudioPlayer{m.music, m.fadingOut} {
+		if music == nil {
+			continue
+		}
+		if paused {
+			music.Pause()
+		} else {
+			music.Play()
+		}
+	}
+	for _, v := range m.voices {
+		if paused {
+			v.player.Pause()
+		} else {
+			v.player.Play()
+		}
+	}
+}
+
+// Play a sound effect on the effects bus, panned from -1 (left) to 1 (right) with a gain between 0 and 1
+func (m *Mixer) Play(se []byte, pan, gain float64) {
+	if len(se) == 0 {
+		log.Printf("cannot play empty sound")
+		return
+	}
+	if m.muted || m.paused {
+		return
+	}
+	sound := &se[0]
+
+	// make room for the new voice: first for this sound, then for all sounds
+	count := 0
+	for _, v := range m.voices {
+		if v.sound == sound {
+			count++
+		}
+	}
+	if count >= MaxVoicesPerSound {
+		m.steal(sound)
+	}
+	if len(m.voices) >= MaxVoices {
+		m.steal(nil)
+	}
+
+	player, err := m.audioContext.NewPlayer(newPanStream(se, pan))
+	if err != nil {
+		log.Printf("cannot play sound: %v", err)
+		return
+	}
+	player.SetVolume(m.volume(m.effectsVolume) * gain)
+	player.Play()
+	m.voices = append(m.voices, &voice{
+		player: player,
+		sound:  sound,
+		gain:   gain,
+	})
+}
+
+// Update the music crossfade and release the sound effects which have finished playing
+func (m *Mixer) Update() {
+	if m.paused {
+		return
+	}
+	if m.duck > 0 {
+		m.duck--
+	}
+	if m.fadingOut != nil {
+		m.fade++
+		if m.fade >= MusicFadeTime {
+			m.fadingOut.Pause()
+			m.fadingOut = nil
+		}
+	}
+	m.updateMusicVolume()
+
+	playing := m.voices[:0]
+	for _, v := range m.voices {
+		if v.player.IsPlaying() {
+			playing = append(playing, v)
+			continue
+		}
+		v.player.Close()
+	}
+	// clear the references left at the end of the slice
+	for i := len(playing); i < len(m.voices); i++ {
+		m.voices[i] = nil
+	}
+	m.voices = playing
+}
+
+// Voices returns the number of sound effects currently playing
+func (m *Mixer) Voices() int {
+	return len(m.voices)
+}
+
+// Close stops all sounds
+func (m *Mixer) Close() error {
+	for _, v := range m.voices {
+		v.player.Close()
+	}
+	m.voices = m.voices[:0]
+	var err error
+	for track, music := range m.tracks {
+		if track != music.Track() {
+			// shared player, closed under its own name
+			continue
+		}
+		closeErr := music.Close()
+		if closeErr != nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// loadTrack returns the music player of the track, loading it the first time it's needed
+func (m *Mixer) loadTrack(track string) (*AudioPlayer, error) {
+	if music, found := m.tracks[track]; found {
+		return music, nil
+	}
+	music, err := NewAudioPlayer(m.audioContext, track)
+	if err != nil {
+		return nil, err
+	}
+	// when the track was not found, another one was loaded instead: share the same player for both
+	if loaded, found := m.tracks[music.Track()]; found {
+		music.Close()
+		music = loaded
+	}
+	m.tracks[track] = music
+	m.tracks[music.Track()] = music
+	return music, nil
+}
+
+// updateMusicVolume sets the volume of the music tracks according to the crossfade and ducking
+func (m *Mixer) updateMusicVolume() {
+	music, fadingOut := m.musicVolumes()
+	m.music.SetVolume(music)
+	if m.fadingOut != nil {
+		m.fadingOut.SetVolume(fadingOut)
+	}
+}
+
+// musicVolumes returns the volume of the music track playing, and of the previous track fading out
+func (m *Mixer) musicVolumes() (float64, float64) {
+	volume := m.volume(m.musicVolume)
+	if m.duck > 0 {
+		volume *= MusicDuckVolume
+	}
+	if m.fadingOut == nil {
+		return volume, 0
+	}
+	fade := float64(m.fade) / MusicFadeTime
+	return volume * fade, volume * (1 - fade)
+}
+
+// steal stops the oldest voice playing the sound (or the oldest voice of all if sound is nil)
+func (m *Mixer) steal(sound *byte) {
+	for i, v := range m.voices {
+		if sound != nil && v.sound != sound {
+			continue
+		}
+		v.player.Close()
+		last := len(m.voices) - 1
+		copy(m.voices[i:], m.voices[i+1:])
+		m.voices[last] = nil
+		m.voices = m.voices[:last]
+		return
+	}
+}
+
+func (m *Mixer) volume(volume float64) float64 {
+	if m.muted {
+		return 0
+	}
+	return volume
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMixer creates a mixer playing the default music track
+func newTestMixer(t *testing.T, musicVolume, effectsVolume float64) *Mixer {
+	t.Helper()
+	audioContext := audio.CurrentContext()
+	if audioContext == nil {
+		audioContext = audio.NewContext(SampleRate)
+	}
+	mixer, err := NewMixer(audioContext, musicDefault, musicVolume, effectsVolume)
+	require.NoError(t, err)
+	t.Cleanup(func() { mixer.Close() })
+	return mixer
+}
+
+func TestVolume(t *testing.T) {
+	testData := []struct {
+		value    int
+		expected float64
+	}{
+		{0, 0},
+		{MaxVolume / 4, 0.25},
+		{MaxVolume / 2, 0.5},
+		{MaxVolume, 1},
+	}
+
+	for _, testItem := range testData {
+		assert.InDelta(t, testItem.expected, Volume(testItem.value), 0.0001)
+	}
+}
+
+func TestMusicVolumes(t *testing.T) {
+	testData := []struct {
+		name      string
+		volume    float64
+		muted     bool
+		duck      int
+		fade      int
+		crossfade bool
+		music     float64
+		fadingOut float64
+	}{
+		{"normal", 0.8, false, 0, 0, false, 0.8, 0},
+		{"muted", 0.8, true, 0, 0, false, 0, 0},
+		{"ducked", 0.8, false, 10, 0, false, 0.8 * MusicDuckVolume, 0},
+		{"crossfade start", 0.8, false, 0, 0, true, 0, 0.8},
+		{"crossfade middle", 0.8, false, 0, MusicFadeTime / 2, true, 0.4, 0.4},
+		{"crossfade end", 0.8, false, 0, MusicFadeTime, true, 0.8, 0},
+		{"muted crossfade", 0.8, true, 0, MusicFadeTime / 2, true, 0, 0},
+		{"ducked crossfade", 1, false, 10, MusicFadeTime / 2, true, MusicDuckVolume / 2, MusicDuckVolume / 2},
+	}
+
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			mixer := &Mixer{
+				musicVolume: testItem.volume,
+				muted:       testItem.muted,
+				duck:        testItem.duck,
+				fade:        testItem.fade,
+			}
+			if testItem.crossfade {
+				mixer.fadingOut = &AudioPlayer{}
+			}
+			music, fadingOut := mixer.musicVolumes()
+			assert.InDelta(t, testItem.music, music, 0.0001)
+			assert.InDelta(t, testItem.fadingOut, fadingOut, 0.0001)
+		})
+	}
+}
+
+func TestMixerMute(t *testing.T) {
+	mixer := newTestMixer(t, 0.5, 1)
+	se := make([]byte, SampleRate)
+
+	mixer.ToggleMute()
+	assert.True(t, mixer.IsMuted())
+	music, _ := mixer.musicVolumes()
+	assert.Zero(t, music)
+	mixer.Play(se, 0, 1)
+	assert.Equal(t, 0, mixer.Voices())
+
+	mixer.ToggleMute()
+	assert.False(t, mixer.IsMuted())
+	music, _ = mixer.musicVolumes()
+	assert.Equal(t, 0.5, music)
+	mixer.Play(se, 0, 1)
+	assert.Equal(t, 1, mixer.Voices())
+}
+
+func TestMixerPaused(t *testing.T) {
+	mixer := newTestMixer(t, 0.5, 1)
+	se := make([]byte, SampleRate)
+
+	mixer.SetPaused(true)
+	mixer.Play(se, 0, 1)
+	assert.Equal(t, 0, mixer.Voices())
+
+	mixer.SetPaused(false)
+	mixer.Play(se, 0, 1)
+	assert.Equal(t, 1, mixer.Voices())
+}
+
+func TestMixerVoiceLimit(t *testing.T) {
+	mixer := newTestMixer(t, 0.5, 1)
+	sounds := make([][]byte, MaxVoices+2)
+	for i := range sounds {
+		sounds[i] = make([]byte, SampleRate)
+	}
+
+	// the same sound can only play a few times at once
+	for i := 0; i < MaxVoicesPerSound+2; i++ {
+		mixer.Play(sounds[0], 0, 1)
+	}
+	assert.Equal(t, MaxVoicesPerSound, mixer.Voices())
+
+	// and all the sounds together are limited too
+	for _, se := range sounds[1:] {
+		mixer.Play(se, 0, 1)
+	}
+	assert.Equal(t, MaxVoices, mixer.Voices())
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"image/color"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/creativeprojects/cavern/lib"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	optionsTop        = 40.0
+	optionsLeft       = 60.0
+	optionsRight      = 740.0
+	optionsLineHeight = 32.0
+	optionsLines      = 13 // number of lines fitting on the screen
+)
+
+var optionsBackground = color.RGBA{0, 0, 0, 0xc0}
+
+// optionItem is a line in the options screen
+type optionItem struct {
+	label  string // message key of the label
+	value  func() string
+	change func(delta int) // called with -1 or 1 when pressing left or right
+	action func()          // called when pressing enter or space
+}
+
+// Options is the screen where the settings can be changed
+type Options struct {
+	settings   *Settings
+	items      []optionItem
+	selected   int
+	scroll     int       // first item displayed
+	waitingKey Action    // action waiting for a new key to be pressed, empty when none
+	returnTo   GameState // state to go back to when leaving the options screen
+	arrow      *ebiten.Image
+	keys       []ebiten.Key
+	closed     bool
+	cursor     func() (float64, float64) // position of the mouse cursor on the game screen
+	cursorX    float64
+	cursorY    float64
+}
+
+// NewOptions creates the options screen. The onChange callback is called every time a setting is changed,
+// and the cursor function returns the position of the mouse on the game screen.
+func NewOptions(settings *Settings, onChange func(), cursor func() (float64, float64)) *Options {
+	o := &Options{
+		settings: settings,
+		arrow:    Image(ImageCursor),
+		cursor:   cursor,
+		keys:     make([]ebiten.Key, 0, 10),
+	}
+	o.items = []optionItem{
+		{
+			label: "options.music_volume",
+			value: func() string { return strconv.Itoa(settings.MusicVolume) },
+			change: func(delta int) {
+				settings.MusicVolume = clamp(settings.MusicVolume+delta*VolumeStep, 0, MaxVolume)
+				onChange()
+			},
+		},
+		{
+			label: "options.effects_volume",
+			value: func() string { return strconv.Itoa(settings.EffectsVolume) },
+			change: func(delta int) {
+				settings.EffectsVolume = clamp(settings.EffectsVolume+delta*VolumeStep, 0, MaxVolume)
+				onChange()
+			},
+		},
+	}
+	for _, action := range Actions {
+		action := action
+		o.items = append(o.items, optionItem{
+			label:  "options.key." + string(action),
+			value:  func() string { return keyName(settings.Key(action)) },
+			action: func() { o.waitingKey = action },
+		})
+	}
+	o.items = append(o.items,
+		optionItem{
+			label: "options.fullscreen",
+			value: func() string { return onOff(settings.Fullscreen) },
+			change: func(delta int) {
+				settings.Fullscreen = !settings.Fullscreen
+				onChange()
+			},
+		},
+		optionItem{
+			label: "options.window_scale",
+			value: func() string { return strconv.Itoa(settings.WindowScale) },
+			change: func(delta int) {
+				settings.WindowScale = clamp(settings.WindowScale+delta, 1, MaxWindowScale)
+				onChange()
+			},
+		},
+		optionItem{
+			label: "options.scale_mode",
+			value: func() string { return T("scale_mode." + settings.ScaleMode.String()) },
+			change: func(delta int) {
+				settings.ScaleMode = lib.ScaleMode(clamp(int(settings.ScaleMode)+delta, int(lib.ScaleSmooth), int(lib.ScalePixelPerfect)))
+				onChange()
+			},
+		},
+		optionItem{
+			label: "options.difficulty",
+			value: func() string { return T("difficulty." + strings.ToLower(settings.Difficulty.String())) },
+			change: func(delta int) {
+				settings.Difficulty = Difficulty(clamp(int(settings.Difficulty)+delta, int(DifficultyEasy), int(DifficultyHard)))
+				onChange()
+			},
+		},
+		optionItem{
+			label: "options.language",
+			value: func() string { return messages.Name(messages.Language()) },
+			change: func(delta int) {
+				languages := messages.Languages()
+				current := 0
+				for i, language := range languages {
+					if language == messages.Language() {
+						current = i
+					}
+				}
+				settings.Language = languages[(current+len(languages)+delta)%len(languages)]
+				onChange()
+			},
+		},
+		optionItem{
+			label: "options.interpolation",
+			value: func() string { return onOff(settings.Interpolation) },
+			change: func(delta int) {
+				settings.Interpolation = !settings.Interpolation
+				onChange()
+			},
+		},
+		optionItem{
+			label: "options.scanlines",
+			value: func() string { return onOff(settings.Scanlines) },
+			change: func(delta int) {
+				settings.Scanlines = !settings.Scanlines
+				onChange()
+			},
+		},
+		optionItem{
+			label: "options.curvature",
+			value: func() string { return onOff(settings.Curvature) },
+			change: func(delta int) {
+				settings.Curvature = !settings.Curvature
+				onChange()
+			},
+		},
+		optionItem{
+			label: "options.bloom",
+			value: func() string { return onOff(settings.Bloom) },
+			change: func(delta int) {
+				settings.Bloom = !settings.Bloom
+				onChange()
+			},
+		},
+		optionItem{
+			label: "options.colour_blind",
+			value: func() string { return T("colour_blind." + settings.ColourBlind.String()) },
+			change: func(delta int) {
+				settings.ColourBlind = ColourBlindMode(clamp(int(settings.ColourBlind)+delta, int(ColourBlindOff), int(ColourBlindTritanopia)))
+				onChange()
+			},
+		},
+		optionItem{
+			label:  "options.back",
+			action: func() { o.closed = true },
+		},
+	)
+	return o
+}
+
+// Open the options screen from the current game state
+func (o *Options) Open(from GameState) {
+	o.returnTo = from
+	o.selected = 0
+	o.scroll = 0
+	o.waitingKey = ""
+	o.closed = false
+}
+
+// Update handles the navigation in the options screen.
+// It returns the state to go back to once the screen is closed, or StateOptions while still open.
+func (o *Options) Update() GameState {
+	if o.waitingKey != "" {
+		o.keys = inpututil.AppendJustPressedKeys(o.keys[:0])
+		if len(o.keys) > 0 {
+			if o.keys[0] != ebiten.KeyEscape {
+				o.settings.BindKey(o.waitingKey, o.keys[0])
+			}
+			o.waitingKey = ""
+		}
+		return StateOptions
+	}
+
+	// the mouse selects the line under the cursor when it moves, and clicks change the value
+	x, y := o.cursor()
+	hovered := o.itemAt(x, y)
+	if (x != o.cursorX || y != o.cursorY) && hovered >= 0 {
+		o.selected = hovered
+	}
+	o.cursorX, o.cursorY = x, y
+
+	item := o.items[o.selected]
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		o.closed = true
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		o.selected = (o.selected + len(o.items) - 1) % len(o.items)
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		o.selected = (o.selected + 1) % len(o.items)
+	case inpututil.IsKeyJustPressed(ebiten.KeyLeft) && item.change != nil:
+		item.change(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyRight) && item.change != nil:
+		item.change(1)
+	case (inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace)) && item.action != nil:
+		item.action()
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && hovered == o.selected:
+		if item.action != nil {
+			item.action()
+		} else if item.change != nil {
+			item.change(1)
+		}
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) && hovered == o.selected && item.change != nil:
+		item.change(-1)
+	}
+
+	// keep the selected item on the screen
+	if o.selected < o.scroll {
+		o.scroll = o.selected
+	} else if o.selected >= o.scroll+optionsLines {
+		o.scroll = o.selected - optionsLines + 1
+	}
+
+	if !o.closed {
+		return StateOptions
+	}
+	err := o.settings.Save()
+	if err != nil {
+		log.Printf("cannot save settings: %v", err)
+	}
+	return o.returnTo
+}
+
+// From returns the game state the options screen was opened from
+func (o *Options) From() GameState {
+	return o.returnTo
+}
+
+// Draw the options screen on top of the game
+func (o *Options) Draw(screen *ebiten.Image) {
+	vector.DrawFilledRect(screen, 0, 0, WindowWidth, WindowHeight, optionsBackground, false)
+
+	op := &ebiten.DrawImageOptions{}
+	for i, item := range o.items {
+		if i < o.scroll || i >= o.scroll+optionsLines {
+			continue
+		}
+		y := optionsTop + float64(i-o.scroll)*optionsLineHeight
+		if i == o.selected {
+			op.GeoM.Reset()
+			op.GeoM.Translate(optionsLeft-40, y)
+			screen.DrawImage(o.arrow, op)
+		}
+		style := menuStyle
+		if i == o.selected {
+			style = menuSelectedStyle
+		}
+		textRenderer.Draw(screen, T(item.label), optionsLeft, y, style)
+		value := ""
+		if item.value != nil {
+			value = item.value()
+		}
+		if i == o.selected && o.waitingKey != "" {
+			value = T("options.press_key")
+		}
+		textRenderer.Draw(screen, value, optionsRight, y, menuValueStyle)
+	}
+}
+
+// itemAt returns the index of the line displayed at these game screen coordinates, or -1
+func (o *Options) itemAt(x, y float64) int {
+	if x < optionsLeft-40 || x > optionsRight || y < optionsTop {
+		return -1
+	}
+	i := o.scroll + int((y-optionsTop)/optionsLineHeight)
+	if i >= len(o.items) || i >= o.scroll+optionsLines {
+		return -1
+	}
+	return i
+}
+
+// keyName returns a key name to display
+func keyName(key ebiten.Key) string {
+	return strings.ToUpper(key.String())
+}
+
+func onOff(value bool) string {
+	if value {
+		return T("on")
+	}
+	return T("off")
+}
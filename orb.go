@@ -9,27 +9,23 @@ import (
 
 type Orb struct {
 	*Collide
-	blowImages       []*ebiten.Image
-	trapImages       [2][]*ebiten.Image
-	popSounds        [][]byte
+	animBlow         *lib.Animation
+	animTrap         [2]*lib.Animation // by robot type
 	direction        float64
 	active           bool
 	floating         bool
 	timer            int
 	blownFrames      int
 	trappedEnemyType RobotType
+	sparkles         *lib.Emitter // while an enemy is trapped
 }
 
-func NewOrb(level *Level) *Orb {
+func NewOrb(level *Level, particles *lib.ParticleSystem) *Orb {
 	return &Orb{
-		Collide: NewCollide(level, lib.NewSprite(lib.XCentre, lib.YBottom)),
-		blowImages: []*ebiten.Image{images["orb0"], images["orb1"], images["orb2"],
-			images["orb3"], images["orb4"], images["orb5"], images["orb6"]},
-		trapImages: [2][]*ebiten.Image{
-			{images["trap00"], images["trap01"], images["trap02"], images["trap03"], images["trap04"], images["trap05"], images["trap06"], images["trap07"]},
-			{images["trap10"], images["trap11"], images["trap12"], images["trap13"], images["trap14"], images["trap15"], images["trap16"], images["trap17"]},
-		},
-		popSounds: [][]byte{sounds["pop0"], sounds["pop1"], sounds["pop2"], sounds["pop3"]},
+		Collide:  NewCollide(level, lib.NewSprite(lib.XCentre, lib.YBottom)),
+		animBlow: Animation(AnimationOrbBlow),
+		animTrap: [2]*lib.Animation{Animation(AnimationOrbTrapNormal), Animation(AnimationOrbTrapAggressive)},
+		sparkles: particles.NewEmitter(EffectTrapSparkle),
 	}
 }
 
@@ -43,10 +39,11 @@ func (o *Orb) Start(x, y, direction float64) *Orb {
 	o.active = true
 	o.floating = false
 	o.trappedEnemyType = RobotNone
+	o.sparkles.Stop()
 	o.direction = direction
 	o.blownFrames = 6
-	o.MoveTo(x, y)
-	o.SetSequenceFunc(imageSequence).Animate(o.blowImages, nil, 3, true)
+	o.Teleport(x, y)
+	o.Play(o.animBlow)
 	return o
 }
 
@@ -61,7 +58,8 @@ func (o *Orb) IsActive() bool {
 func (o *Orb) TrapEnemy(robotType RobotType) {
 	o.trappedEnemyType = robotType
 	o.floating = true
-	o.SetSequenceFunc(nil).Animate(o.trapImages[robotType-1], nil, 4, true)
+	o.Play(o.animTrap[robotType-1])
+	o.sparkles.MoveTo(o.X(lib.XCentre), o.Y(lib.YCentre)).Start()
 }
 
 func (o *Orb) EnemyTrapped() bool {
@@ -96,16 +94,21 @@ func (o *Orb) Update(game *Game) {
 	}
 	if o.timer > OrbMaxTimer || o.Y(lib.YBottom) <= -40 {
 		o.active = false
+		o.sparkles.Stop()
 		game.StartPop(PopOrb, o.X(lib.XCentre), o.Y(lib.YBottom))
+		game.particles.Burst(EffectOrbPop, o.X(lib.XCentre), o.Y(lib.YCentre))
 		// create an extra fruit if an enemy was trapped in it
 		if o.trappedEnemyType > RobotNone {
 			fruit := game.CreateFruit(true)
-			fruit.MoveTo(o.X(lib.XCentre), math.Ceil(o.Y(lib.YBottom)))
+			fruit.Teleport(o.X(lib.XCentre), math.Ceil(o.Y(lib.YBottom)))
 		}
-		game.RandomSoundEffect(o.popSounds)
 		return
 	}
+	o.sparkles.MoveTo(o.X(lib.XCentre), o.Y(lib.YCentre))
 	o.Sprite.Update()
+	for _, event := range o.Sprite.Events() {
+		game.EventSound(event, o.X(lib.XCentre), o.Y(lib.YCentre))
+	}
 }
 
 func (o *Orb) Draw(screen *ebiten.Image) {
@@ -114,10 +117,3 @@ func (o *Orb) Draw(screen *ebiten.Image) {
 	}
 	o.Sprite.Draw(screen)
 }
-
-func imageSequence(timer int) int {
-	if timer < 9 {
-		return timer / 3
-	}
-	return 3 + int(math.Mod(math.Floor((float64(timer)-9)/8), 4))
-}
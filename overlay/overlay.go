@@ -0,0 +1,195 @@
+// Package overlay stacks file systems on top of each other: a file in an upper layer
+// replaces the file with the same name in the layers below it.
+//
+// It is used to load resource packs (a directory or a zip file) over the embedded assets.
+package overlay
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FS is a read-only file system made of layers
+type FS struct {
+	layers []fs.FS // the top layer first
+}
+
+// New creates a file system from layers, the top layer first
+func New(layers ...fs.FS) *FS {
+	return &FS{
+		layers: layers,
+	}
+}
+
+// Open the file from the top-most layer containing it. Directories list the files of all the layers.
+func (o *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	var firstErr error
+	for _, layer := range o.layers {
+		file, err := layer.Open(name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		info, err := file.Stat()
+		if err != nil || !info.IsDir() {
+			return file, err
+		}
+		entries, err := o.ReadDir(name)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return &dir{File: file, entries: entries}, nil
+	}
+	return nil, firstErr
+}
+
+// ReadFile reads the file from the top-most layer containing it
+func (o *FS) ReadFile(name string) ([]byte, error) {
+	var firstErr error
+	for _, layer := range o.layers {
+		data, err := fs.ReadFile(layer, name)
+		if err == nil {
+			return data, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// ReadDir returns the entries of the directory in all the layers, sorted by name.
+// When the same name is in more than one layer, the entry from the top-most layer is returned.
+func (o *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	merged := make(map[string]fs.DirEntry)
+	var firstErr error
+	found := false
+	for _, layer := range o.layers {
+		entries, err := fs.ReadDir(layer, name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		found = true
+		for _, entry := range entries {
+			if _, exists := merged[entry.Name()]; !exists {
+				merged[entry.Name()] = entry
+			}
+		}
+	}
+	if !found {
+		return nil, firstErr
+	}
+	entries := make([]fs.DirEntry, 0, len(merged))
+	for _, entry := range merged {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+// Stat returns the file information from the top-most layer containing the file
+func (o *FS) Stat(name string) (fs.FileInfo, error) {
+	var firstErr error
+	for _, layer := range o.layers {
+		info, err := fs.Stat(layer, name)
+		if err == nil {
+			return info, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// OpenLayer opens a directory or a zip file to use as a layer.
+// When all the files of a zip file are in a folder named after the zip file (reskin/ in reskin.zip),
+// that folder is used as the root of the layer.
+// The closer releases the zip file, and does nothing for a directory.
+func OpenLayer(name string) (fs.FS, io.Closer, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return os.DirFS(name), nopCloser{}, nil
+	}
+	if !strings.EqualFold(filepath.Ext(name), ".zip") {
+		return nil, nil, fmt.Errorf("%s: a resource pack must be a directory or a zip file", name)
+	}
+	archive, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	root := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	inFolder, err := onlyFolder(archive, root)
+	if err != nil {
+		archive.Close()
+		return nil, nil, err
+	}
+	if !inFolder {
+		return archive, archive, nil
+	}
+	sub, err := fs.Sub(archive, root)
+	if err != nil {
+		archive.Close()
+		return nil, nil, err
+	}
+	return sub, archive, nil
+}
+
+// onlyFolder returns true when the folder is the only entry at the root of the file system
+func onlyFolder(fsys fs.FS, folder string) (bool, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return false, err
+	}
+	return len(entries) == 1 && entries[0].IsDir() && entries[0].Name() == folder, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// dir is a directory opened from the top-most layer, listing the files of all the layers
+type dir struct {
+	fs.File
+	entries []fs.DirEntry
+	offset  int
+}
+
+// ReadDir implements fs.ReadDirFile
+func (d *dir) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+	d.offset += count
+	return remaining[:count], nil
+}
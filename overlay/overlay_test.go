@@ -0,0 +1,138 @@
+package overlay
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	baseLayer = fstest.MapFS{
+		"images/robot.png":  {Data: []byte("base robot")},
+		"images/player.png": {Data: []byte("base player")},
+		"sounds/jump.ogg":   {Data: []byte("base jump")},
+	}
+	packLayer = fstest.MapFS{
+		"images/robot.png": {Data: []byte("pack robot")},
+		"images/extra.png": {Data: []byte("pack extra")},
+	}
+)
+
+func TestOverlayReadFile(t *testing.T) {
+	o := New(packLayer, baseLayer)
+
+	testData := []struct {
+		name     string
+		expected string
+	}{
+		{"images/robot.png", "pack robot"},
+		{"images/player.png", "base player"},
+		{"images/extra.png", "pack extra"},
+		{"sounds/jump.ogg", "base jump"},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			data, err := fs.ReadFile(o, testItem.name)
+			require.NoError(t, err)
+			assert.Equal(t, testItem.expected, string(data))
+
+			// also through Open
+			file, err := o.Open(testItem.name)
+			require.NoError(t, err)
+			defer file.Close()
+			info, err := file.Stat()
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(testItem.expected)), info.Size())
+		})
+	}
+
+	_, err := fs.ReadFile(o, "images/missing.png")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = o.Open("../outside")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
+
+func TestOverlayGlob(t *testing.T) {
+	o := New(packLayer, baseLayer)
+
+	names, err := fs.Glob(o, "images/*.png")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"images/extra.png", "images/player.png", "images/robot.png"}, names)
+
+	names, err = fs.Glob(o, "sounds/*.ogg")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sounds/jump.ogg"}, names)
+}
+
+func TestOverlayFSTest(t *testing.T) {
+	err := fstest.TestFS(New(packLayer, baseLayer),
+		"images/robot.png", "images/player.png", "images/extra.png", "sounds/jump.ogg")
+	assert.NoError(t, err)
+}
+
+func TestOpenLayerDirectory(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "images"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "images", "robot.png"), []byte("dir robot"), 0o644))
+
+	layer, closer, err := OpenLayer(root)
+	require.NoError(t, err)
+	defer closer.Close()
+
+	data, err := fs.ReadFile(New(layer, baseLayer), "images/robot.png")
+	require.NoError(t, err)
+	assert.Equal(t, "dir robot", string(data))
+}
+
+func TestOpenLayerZip(t *testing.T) {
+	testData := []struct {
+		zipName  string
+		filename string
+	}{
+		{"pack.zip", "images/robot.png"},
+		{"reskin.zip", "reskin/images/robot.png"}, // folder named after the zip file
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.zipName, func(t *testing.T) {
+			zipName := filepath.Join(t.TempDir(), testItem.zipName)
+			writeZip(t, zipName, testItem.filename, "zip robot")
+
+			layer, closer, err := OpenLayer(zipName)
+			require.NoError(t, err)
+			defer closer.Close()
+
+			data, err := fs.ReadFile(New(layer, baseLayer), "images/robot.png")
+			require.NoError(t, err)
+			assert.Equal(t, "zip robot", string(data))
+		})
+	}
+}
+
+func TestOpenLayerInvalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "pack.txt")
+	require.NoError(t, os.WriteFile(filename, []byte("not a pack"), 0o644))
+	_, _, err := OpenLayer(filename)
+	assert.Error(t, err)
+
+	_, _, err = OpenLayer(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func writeZip(t *testing.T, zipName, filename, content string) {
+	t.Helper()
+	file, err := os.Create(zipName)
+	require.NoError(t, err)
+	defer file.Close()
+	writer := zip.NewWriter(file)
+	entry, err := writer.Create(filename)
+	require.NoError(t, err)
+	_, err = entry.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"math"
+)
+
+// panStream plays a 16 bits stereo sound with a different volume on each channel
+type panStream struct {
+	data    []byte
+	pos     int64
+	left    float64
+	right   float64
+	frame   [4]byte // stereo frame panned for a buffer too small to hold it
+	pending []byte  // rest of the frame, returned by the next reads
+}
+
+// newPanStream creates a stream of the sound panned from -1 (left) to 1 (right)
+func newPanStream(data []byte, pan float64) *panStream {
+	left, right := 1.0, 1.0
+	if pan > 0 {
+		left -= pan
+	} else {
+		right += pan
+	}
+	return &panStream{
+		data:  data,
+		left:  left,
+		right: right,
+	}
+}
+
+// Read implements io.Reader. Each read stops at the end of a stereo frame, so the next one starts with a left sample.
+func (s *panStream) Read(p []byte) (int, error) {
+	if len(s.pending) > 0 {
+		n := copy(p, s.pending)
+		s.pending = s.pending[n:]
+		return n, nil
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	length := int64(len(s.data))
+	if s.pos >= length {
+		return 0, io.EOF
+	}
+	end := s.pos + int64(len(p))
+	if end < length {
+		end -= end % 4
+	} else {
+		end = length
+	}
+	if end <= s.pos {
+		// the buffer is smaller than the rest of the frame: pan the frame apart
+		end = s.pos - s.pos%4 + 4
+		if end > length {
+			end = length
+		}
+		s.pending = s.frame[:copy(s.frame[:], s.data[s.pos:end])]
+		s.pan(s.pending)
+		s.pos = end
+		n := copy(p, s.pending)
+		s.pending = s.pending[n:]
+		return n, nil
+	}
+	n := copy(p, s.data[s.pos:end])
+	s.pan(p[:n])
+	s.pos = end
+	return n, nil
+}
+
+// pan applies the volume of each channel to the samples read from the current position
+func (s *panStream) pan(samples []byte) {
+	for i := 0; i+1 < len(samples); i += 2 {
+		gain := s.left
+		if (s.pos+int64(i))/2%2 == 1 {
+			gain = s.right
+		}
+		sample := float64(int16(uint16(samples[i]) | uint16(samples[i+1])<<8))
+		value := int16(sample * gain)
+		samples[i] = byte(value)
+		samples[i+1] = byte(value >> 8)
+	}
+}
+
+// Seek implements io.Seeker
+func (s *panStream) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.pos
+	case io.SeekEnd:
+		offset += int64(len(s.data))
+	default:
+		return s.pos, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return s.pos, errors.New("negative position")
+	}
+	// never start in the middle of a sample
+	s.pos = offset - offset%2
+	s.pending = nil
+	return s.pos, nil
+}
+
+// panning returns the stereo position (-1 to 1) of an x coordinate, relative to the arena
+func panning(x float64) float64 {
+	pan := (x-ArenaLeft)/(ArenaRight-ArenaLeft)*2 - 1
+	return math.Max(-1, math.Min(1, pan)) * MaxPan
+}
+
+// attenuation returns the volume multiplier of a sound coming from these coordinates
+func attenuation(x, y float64) float64 {
+	if x < 0 || x > WindowWidth || y < 0 || y > WindowHeight {
+		return OffScreenVolume
+	}
+	return 1
+}
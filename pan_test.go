@@ -0,0 +1,80 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPanStream(t *testing.T) {
+	// two stereo frames of 16 bits samples: (1000, 1000) and (-1000, -1000)
+	data := []byte{0xe8, 0x03, 0xe8, 0x03, 0x18, 0xfc, 0x18, 0xfc}
+
+	testData := []struct {
+		pan   float64
+		left  int16
+		right int16
+	}{
+		{0, 1000, 1000},
+		{1, 0, 1000},
+		{-1, 1000, 0},
+		{0.5, 500, 1000},
+	}
+	for _, testItem := range testData {
+		stream := newPanStream(data, testItem.pan)
+		buffer, err := io.ReadAll(stream)
+		require.NoError(t, err)
+		require.Len(t, buffer, len(data))
+		// data has not changed
+		assert.Equal(t, byte(0xe8), data[0])
+
+		samples := make([]int16, len(buffer)/2)
+		for i := range samples {
+			samples[i] = int16(uint16(buffer[i*2]) | uint16(buffer[i*2+1])<<8)
+		}
+		assert.Equal(t, []int16{testItem.left, testItem.right, -testItem.left, -testItem.right}, samples)
+	}
+}
+
+func TestPanStreamSeek(t *testing.T) {
+	data := []byte{0xe8, 0x03, 0xe8, 0x03, 0x18, 0xfc, 0x18, 0xfc}
+	stream := newPanStream(data, 1)
+	pos, err := stream.Seek(6, io.SeekStart)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), pos)
+
+	buffer, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x18, 0xfc}, buffer)
+}
+
+func TestPanning(t *testing.T) {
+	assert.Equal(t, -MaxPan, panning(ArenaLeft))
+	assert.Equal(t, 0.0, panning((ArenaLeft+ArenaRight)/2))
+	assert.Equal(t, MaxPan, panning(ArenaRight))
+	assert.Equal(t, MaxPan, panning(WindowWidth+100))
+}
+
+func TestPanStreamSmallReads(t *testing.T) {
+	// three stereo frames: (1000, 1000), (-1000, -1000) and (1000, 1000)
+	data := []byte{0xe8, 0x03, 0xe8, 0x03, 0x18, 0xfc, 0x18, 0xfc, 0xe8, 0x03, 0xe8, 0x03}
+	expected, err := io.ReadAll(newPanStream(data, 1))
+	require.NoError(t, err)
+
+	for _, size := range []int{1, 2, 3, 5, 6, 7} {
+		stream := newPanStream(data, 1)
+		buffer := make([]byte, 0, len(data))
+		chunk := make([]byte, size)
+		for {
+			n, err := stream.Read(chunk)
+			buffer = append(buffer, chunk[:n]...)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+		}
+		assert.Equal(t, expected, buffer, "reading %d bytes at a time", size)
+	}
+}
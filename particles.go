@@ -0,0 +1,113 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/creativeprojects/cavern/lib"
+)
+
+// Particle effects
+var (
+	// EffectOrbPop bursts out of an orb when it pops
+	EffectOrbPop = &lib.ParticleEffect{
+		Count:          16,
+		Radius:         10,
+		Lifetime:       20,
+		LifetimeSpread: 10,
+		Speed:          2.5,
+		SpeedSpread:    1,
+		AngleSpread:    math.Pi,
+		Gravity:        0.05,
+		Drag:           0.04,
+		Size:           [2]float64{4, 1},
+		Colour:         [2]color.NRGBA{{0xe0, 0xf8, 0xff, 0xff}, {0x40, 0xa0, 0xff, 0x00}},
+	}
+	// EffectTrap bursts out of a robot when it's trapped in an orb
+	EffectTrap = &lib.ParticleEffect{
+		Count:          24,
+		Radius:         5,
+		Lifetime:       25,
+		LifetimeSpread: 15,
+		Speed:          3,
+		SpeedSpread:    1.5,
+		AngleSpread:    math.Pi,
+		Gravity:        0.1,
+		Drag:           0.03,
+		Size:           [2]float64{3, 1},
+		Colour:         [2]color.NRGBA{{0xff, 0xf0, 0x80, 0xff}, {0xff, 0x60, 0x00, 0x00}},
+	}
+	// EffectTrapSparkle floats out of an orb while a robot is trapped in it
+	EffectTrapSparkle = &lib.ParticleEffect{
+		Rate:           0.3,
+		Radius:         18,
+		Lifetime:       30,
+		LifetimeSpread: 10,
+		Speed:          0.5,
+		SpeedSpread:    0.3,
+		Angle:          -math.Pi / 2,
+		AngleSpread:    math.Pi / 4,
+		Size:           [2]float64{2, 1},
+		Colour:         [2]color.NRGBA{{0xff, 0xff, 0xc0, 0xff}, {0xff, 0xff, 0x80, 0x00}},
+	}
+	// EffectsBoltImpact bounce off a wall hit by a bolt going left or right
+	EffectsBoltImpact = [2]*lib.ParticleEffect{boltImpact(0), boltImpact(math.Pi)}
+	// EffectLandingDust rises from the feet of the player landing on a block
+	EffectLandingDust = &lib.ParticleEffect{
+		Count:          8,
+		Radius:         4,
+		Lifetime:       15,
+		LifetimeSpread: 10,
+		Speed:          1,
+		SpeedSpread:    0.5,
+		Angle:          -math.Pi / 2,
+		AngleSpread:    math.Pi / 2,
+		Gravity:        0.02,
+		Drag:           0.08,
+		Size:           [2]float64{3, 5},
+		Colour:         [2]color.NRGBA{{0xc0, 0xb8, 0xa8, 0xa0}, {0x80, 0x78, 0x70, 0x00}},
+	}
+	// EffectDeath bursts out of the player losing a life
+	EffectDeath = &lib.ParticleEffect{
+		Count:          40,
+		Radius:         10,
+		Lifetime:       30,
+		LifetimeSpread: 20,
+		Speed:          4,
+		SpeedSpread:    2,
+		AngleSpread:    math.Pi,
+		Gravity:        0.15,
+		Drag:           0.02,
+		Size:           [2]float64{4, 1},
+		Colour:         [2]color.NRGBA{{0xff, 0xff, 0xff, 0xff}, {0xff, 0x20, 0x20, 0x00}},
+	}
+	// EffectDeathSmoke trails behind the player falling off the screen after losing a life
+	EffectDeathSmoke = &lib.ParticleEffect{
+		Rate:           1,
+		Radius:         8,
+		Lifetime:       25,
+		LifetimeSpread: 10,
+		Speed:          0.5,
+		Angle:          -math.Pi / 2,
+		AngleSpread:    math.Pi / 3,
+		Size:           [2]float64{4, 8},
+		Colour:         [2]color.NRGBA{{0x60, 0x60, 0x60, 0xc0}, {0x20, 0x20, 0x20, 0x00}},
+	}
+)
+
+// boltImpact returns the sparks of a bolt hitting a wall, flying back in the direction of the angle
+func boltImpact(angle float64) *lib.ParticleEffect {
+	return &lib.ParticleEffect{
+		Count:          10,
+		Radius:         2,
+		Lifetime:       12,
+		LifetimeSpread: 8,
+		Speed:          3,
+		SpeedSpread:    1.5,
+		Angle:          angle,
+		AngleSpread:    math.Pi / 3,
+		Gravity:        0.2,
+		Size:           [2]float64{2, 1},
+		Colour:         [2]color.NRGBA{{0xff, 0xff, 0xa0, 0xff}, {0xff, 0x40, 0x00, 0x00}},
+	}
+}
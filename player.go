@@ -1,11 +1,12 @@
 package main
 
 import (
-	"fmt"
 	"math"
+	"strconv"
 
 	"github.com/creativeprojects/cavern/lib"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
 )
 
 type IconType int
@@ -22,27 +23,29 @@ var (
 		40,
 		40,
 	}
+	// hurtFlash turns the player white, keeping the transparency
+	hurtFlash = func() colorm.ColorM {
+		flash := colorm.ColorM{}
+		flash.Scale(0, 0, 0, 1)
+		flash.Translate(1, 1, 1, 0)
+		return flash
+	}()
+	// playerHitbox is used by all the animations of the player, relative to the bottom centre
+	playerHitbox = lib.Hitbox{X: -25, Y: -66, Width: 50, Height: 66}
 )
 
 type Player struct {
 	sprite        *lib.Sprite
 	gravity       *Gravity
-	imageBlank    *ebiten.Image
-	imageStill    *ebiten.Image
-	runLeft       []*ebiten.Image
-	runRight      []*ebiten.Image
-	jumpLeft      *ebiten.Image
-	jumpRight     *ebiten.Image
-	blowLeft      *ebiten.Image
-	blowRight     *ebiten.Image
-	recoilLeft    *ebiten.Image
-	recoilRight   *ebiten.Image
-	imagesFall    [2]*ebiten.Image
+	animStill     *lib.Animation
+	animFall      *lib.Animation
+	animRun       [2]*lib.Animation // left and right
+	animJump      [2]*lib.Animation
+	animBlow      [2]*lib.Animation
+	animRecoil    [2]*lib.Animation
 	iconImages    [3]*ebiten.Image
-	landingSounds [][]byte
-	blowSounds    [][]byte
-	ouchSounds    [][]byte
-	dieSound      []byte
+	landingSounds []SoundName
+	smoke         *lib.Emitter // trailing behind after losing a life
 	demo          bool
 	lives         int
 	health        int
@@ -55,41 +58,35 @@ type Player struct {
 	blowingOrb    *Orb    // orb being blown right now / nil if none
 }
 
-func NewPlayer() *Player {
-	sprite := lib.NewSprite(lib.XCentre, lib.YBottom)
+func NewPlayer(particles *lib.ParticleSystem) *Player {
+	sprite := lib.NewSprite(lib.XCentre, lib.YBottom).SetHitbox(&playerHitbox)
 	return &Player{
 		sprite:        sprite,
-		imageBlank:    images[imagePlayerBlank],
-		imageStill:    images[imagePlayerStill],
-		runLeft:       []*ebiten.Image{images["run00"], images["run01"], images["run02"], images["run03"]},
-		runRight:      []*ebiten.Image{images["run10"], images["run11"], images["run12"], images["run13"]},
-		jumpLeft:      images[imageJumpLeft],
-		jumpRight:     images[imageJumpRight],
-		blowLeft:      images[imageBlowLeft],
-		blowRight:     images[imageBlowRight],
-		recoilLeft:    images[imageRecoilLeft],
-		recoilRight:   images[imageRecoilRight],
-		imagesFall:    [2]*ebiten.Image{images["fall0"], images["fall1"]},
-		iconImages:    [3]*ebiten.Image{images["life"], images["plus"], images["health"]},
-		landingSounds: [][]byte{sounds["land0"], sounds["land1"], sounds["land2"], sounds["land3"]},
-		blowSounds:    [][]byte{sounds["blow0"] /*sounds["blow1"],*/, sounds["blow2"], sounds["blow3"]},
-		ouchSounds:    [][]byte{sounds["ouch0"], sounds["ouch1"], sounds["ouch2"], sounds["ouch3"]},
-		dieSound:      sounds["die0"],
+		animStill:     Animation(AnimationPlayerStill),
+		animFall:      Animation(AnimationPlayerFall),
+		animRun:       [2]*lib.Animation{Animation(AnimationsPlayerRun[0]), Animation(AnimationsPlayerRun[1])},
+		animJump:      [2]*lib.Animation{Animation(AnimationsPlayerJump[0]), Animation(AnimationsPlayerJump[1])},
+		animBlow:      [2]*lib.Animation{Animation(AnimationsPlayerBlow[0]), Animation(AnimationsPlayerBlow[1])},
+		animRecoil:    [2]*lib.Animation{Animation(AnimationsPlayerRecoil[0]), Animation(AnimationsPlayerRecoil[1])},
+		iconImages:    [3]*ebiten.Image{Image(ImageLife), Image(ImagePlus), Image(ImageHealth)},
+		landingSounds: SoundsLand[:],
+		smoke:         particles.NewEmitter(EffectDeathSmoke),
 	}
 }
 
-func (p *Player) Start(level *Level) *Player {
-	p.lives = PlayerStartLives
+func (p *Player) Start(level *Level, lives int) *Player {
+	p.lives = lives
 	p.gravity = NewGravity(level, p.sprite)
 	p.Reset()
-	p.sprite.Animate([]*ebiten.Image{p.imageStill}, nil, 8, true)
+	p.sprite.Play(p.animStill)
 	return p
 }
 
 func (p *Player) Reset() {
 	p.health = PlayerStartHealth
 	p.hurtTimer = PlayerStartInvulnerability
-	p.sprite.MoveTo(WindowWidth/2, 100)
+	p.sprite.Teleport(WindowWidth/2, 100)
+	p.smoke.Stop()
 }
 
 // Hit tests if the coordinates collide with us and returns yes if it does
@@ -105,10 +102,14 @@ func (p *Player) Hit(x, y, directionX float64, game *Game) bool {
 		p.gravity.speedY = -12
 		p.gravity.landed = false
 		p.direction = directionX
-		if p.health >= 0 {
-			game.RandomSoundEffect(p.ouchSounds)
-		} else {
-			game.SoundEffect(p.dieSound)
+		game.Shake()
+		if p.health > 0 {
+			// played here rather than by the recoil animation, which is also shown after a reset
+			game.RandomSoundEffect(SoundsOuch[:], x, y)
+		}
+		if p.health <= 0 {
+			game.particles.Burst(EffectDeath, p.sprite.X(lib.XCentre), p.sprite.Y(lib.YCentre))
+			p.smoke.MoveTo(p.sprite.X(lib.XCentre), p.sprite.Y(lib.YCentre)).Start()
 		}
 	}
 	return collided
@@ -139,54 +140,62 @@ func (p *Player) Update(game *Game) {
 	if p.hurtTimer > 100 && p.health <= 0 {
 		p.Still()
 		p.gravity.UpdateFreeFall()
+		p.smoke.MoveTo(p.sprite.X(lib.XCentre), p.sprite.Y(lib.YCentre))
 		if p.gravity.Y(lib.YCentre) >= WindowHeight*1.5 {
 			p.lives--
 			if p.lives >= 0 {
 				p.Reset()
 			} else {
-				game.state = StateGameOver
+				game.GameOver()
 			}
 		}
 	} else {
 		landed := p.gravity.UpdateFall()
 		if landed {
-			game.RandomSoundEffect(p.landingSounds)
+			game.RandomSoundEffect(p.landingSounds, p.sprite.X(lib.XCentre), p.sprite.Y(lib.YBottom))
+			game.particles.Burst(EffectLandingDust, p.sprite.X(lib.XCentre), p.sprite.Y(lib.YBottom))
 		}
 	}
+	// flash every other frame while invulnerable
+	if p.hurtTimer > 0 && p.hurtTimer%2 == 0 {
+		p.sprite.SetColorMatrix(&hurtFlash)
+	} else {
+		p.sprite.SetColorMatrix(nil)
+	}
 	switch {
-	case p.hurtTimer > 0 && p.hurtTimer%2 == 0:
-		p.sprite.Animation([]*ebiten.Image{p.imageBlank}, nil, 8, true)
-
 	case p.hurtTimer > 100 && p.health > 0 && p.direction == -1:
-		p.sprite.Animation([]*ebiten.Image{p.recoilLeft}, nil, 8, true)
+		p.sprite.Switch(p.animRecoil[0])
 	case p.hurtTimer > 100 && p.health > 0:
-		p.sprite.Animation([]*ebiten.Image{p.recoilRight}, nil, 8, true)
+		p.sprite.Switch(p.animRecoil[1])
 
 	case p.hurtTimer > 100 && p.health <= 0:
-		p.sprite.Animation(p.imagesFall[:], nil, 4, true)
+		p.sprite.Switch(p.animFall)
 
 	case p.blowingOrb != nil && p.direction == -1:
-		p.sprite.Animation([]*ebiten.Image{p.blowLeft}, nil, 8, true)
+		p.sprite.Switch(p.animBlow[0])
 	case p.blowingOrb != nil:
-		p.sprite.Animation([]*ebiten.Image{p.blowRight}, nil, 8, true)
+		p.sprite.Switch(p.animBlow[1])
 
 	case !p.gravity.landed && p.movingX < 0:
-		p.sprite.Animation([]*ebiten.Image{p.jumpLeft}, nil, 8, true)
+		p.sprite.Switch(p.animJump[0])
 
 	case !p.gravity.landed && p.movingX > 0:
-		p.sprite.Animation([]*ebiten.Image{p.jumpRight}, nil, 8, true)
+		p.sprite.Switch(p.animJump[1])
 
 	case p.movingX < 0:
-		p.sprite.Animation(p.runLeft, nil, 8, true)
+		p.sprite.Switch(p.animRun[0])
 
 	case p.movingX > 0:
-		p.sprite.Animation(p.runRight, nil, 8, true)
+		p.sprite.Switch(p.animRun[1])
 
 	default:
-		p.sprite.Animation([]*ebiten.Image{p.imageStill}, nil, 8, true)
+		p.sprite.Switch(p.animStill)
 
 	}
 	p.sprite.Update()
+	for _, event := range p.sprite.Events() {
+		game.EventSound(event, p.sprite.X(lib.XCentre), p.sprite.Y(lib.YCentre))
+	}
 }
 
 // Draw the player on the screen
@@ -196,12 +205,16 @@ func (p *Player) Draw(screen *ebiten.Image) {
 		return
 	}
 	p.sprite.Draw(screen)
+}
 
-	// Draw player score
-	scoreBytes := []byte(fmt.Sprintf("%d", p.score))
-	DrawText(screen, scoreBytes, float64(WindowWidth-2-(CharWidth(0)*len(scoreBytes))), 451)
+// DrawHUD draws the score, health and lives
+func (p *Player) DrawHUD(screen *ebiten.Image) {
+	// no player (demo mode)
+	if p == nil {
+		return
+	}
+	bitmapFont.Draw(screen, strconv.Itoa(p.score), WindowWidth-2, 451, lib.XRight, nil)
 
-	// Draw player score
 	p.DrawHealth(screen)
 }
 
@@ -253,8 +266,8 @@ func (p *Player) Jump() bool {
 	return true
 }
 
-// Eat a fruit or a bonus
-func (p *Player) Eat(fruitType FruitType) {
+// Eat a fruit or a bonus. It returns the points scored
+func (p *Player) Eat(fruitType FruitType) int {
 	switch {
 	case fruitType == ExtraHealth:
 		// cannot have more than "full" health
@@ -262,8 +275,11 @@ func (p *Player) Eat(fruitType FruitType) {
 	case fruitType == ExtraLife:
 		p.lives++
 	default:
-		p.score += (int(fruitType) + 1) * 100
+		points := (int(fruitType) + 1) * 100
+		p.score += points
+		return points
 	}
+	return 0
 }
 
 func (p *Player) StartBlowing(game *Game) {
@@ -283,7 +299,6 @@ func (p *Player) StartBlowing(game *Game) {
 	x := math.Min(730, math.Max(70, p.sprite.X(lib.XCentre)+direction*38))
 	y := p.sprite.Y(lib.YCentre) // -35
 	p.blowingOrb.Start(x, y, direction)
-	game.RandomSoundEffect(p.blowSounds)
 }
 
 // Blowing keeps pushing the orb a bit further
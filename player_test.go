@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/creativeprojects/cavern/lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerLethalHit(t *testing.T) {
+	game := &Game{particles: lib.NewParticleSystem(MaxParticles)}
+	game.layers = game.newLayers()
+	player := NewPlayer(game.particles).Start(nil, PlayerStartLives)
+	player.sprite.MoveTo(400, 300)
+	player.health = 1
+	player.hurtTimer = -1
+
+	assert.True(t, player.Hit(400, 280, 1, game))
+	assert.Equal(t, 0, player.health)
+	assert.True(t, player.smoke.IsActive())
+	assert.Equal(t, EffectDeath.Count, game.particles.Count())
+
+	// invulnerable while falling
+	assert.False(t, player.Hit(400, 280, 1, game))
+	assert.Equal(t, 0, player.health)
+}
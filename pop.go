@@ -14,19 +14,18 @@ const (
 
 // Pop animation
 type Pop struct {
-	images [2][]*ebiten.Image
-	Type   PopType
-	sprite *lib.Sprite
+	animations [2]*lib.Animation
+	Type       PopType
+	sprite     *lib.Sprite
+	expired    bool
 }
 
 // NewPop creates a new blank pop animation.
 func NewPop() *Pop {
 	i := &Pop{
-		images: [2][]*ebiten.Image{
-			{images["pop00"], images["pop01"], images["pop02"], images["pop03"], images["pop04"], images["pop05"], images["pop06"]},
-			{images["pop10"], images["pop11"], images["pop12"], images["pop13"], images["pop14"], images["pop15"], images["pop16"]},
-		},
-		sprite: lib.NewSprite(lib.XCentre, lib.YBottom),
+		animations: [2]*lib.Animation{Animation(AnimationPopFruit), Animation(AnimationPopOrb)},
+		sprite:     lib.NewSprite(lib.XCentre, lib.YBottom),
+		expired:    true,
 	}
 	return i
 }
@@ -34,15 +33,23 @@ func NewPop() *Pop {
 // Start (and restart) the pop animation on coordinates from another sprite (X centre & Y bottom)
 func (i *Pop) Start(popType PopType, x, y float64) *Pop {
 	i.Type = popType
-	i.sprite.MoveTo(x, y).Animate(i.images[i.Type], nil, 2, false)
+	i.expired = false
+	i.sprite.Teleport(x, y).Play(i.animations[i.Type])
 	return i
 }
 
-func (i *Pop) Update() {
+func (i *Pop) Update(game *Game) {
 	if i.HasExpired() {
 		return
 	}
 	i.sprite.Update()
+	for _, event := range i.sprite.Events() {
+		if event == lib.EventDone {
+			i.expired = true
+			continue
+		}
+		game.EventSound(event, i.sprite.X(lib.XCentre), i.sprite.Y(lib.YCentre))
+	}
 }
 
 func (i *Pop) Draw(screen *ebiten.Image) {
@@ -54,5 +61,5 @@ func (i *Pop) Draw(screen *ebiten.Image) {
 
 // HasExpired returns true when the animation is finished
 func (i *Pop) HasExpired() bool {
-	return i.sprite.IsFinished()
+	return i.expired
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/creativeprojects/cavern/lib/tween"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Popup is a text bouncing up for a short while, like the points scored when eating a fruit
+type Popup struct {
+	text  string
+	x     float64
+	y     float64
+	tween *tween.Group
+}
+
+// NewPopup creates a new blank popup.
+func NewPopup() *Popup {
+	return &Popup{}
+}
+
+// Start (and restart) the popup with the text centred on the x coordinate, just above y
+func (p *Popup) Start(text string, x, y float64) *Popup {
+	p.text = text
+	p.x = x
+	p.y = y - popupStyle.Size
+	p.tween = tween.Sequence(
+		tween.Float(&p.y, p.y, p.y-PopupRise, PopupRiseTime, tween.BounceOut),
+		tween.Wait(PopupTime-PopupRiseTime),
+	)
+	return p
+}
+
+func (p *Popup) Update() {
+	if p.HasExpired() {
+		return
+	}
+	p.tween.Update()
+}
+
+func (p *Popup) Draw(screen *ebiten.Image) {
+	if p.HasExpired() {
+		return
+	}
+	textRenderer.Draw(screen, p.text, p.x, p.y, popupStyle)
+}
+
+// HasExpired returns true when the popup is no longer displayed
+func (p *Popup) HasExpired() bool {
+	return p.tween == nil || p.tween.Finished()
+}
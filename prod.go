@@ -9,6 +9,12 @@ var Debug = false
 func (g *Game) displayDebug(screen *ebiten.Image) {
 }
 
+func (g *Game) displayAtlas(screen *ebiten.Image, page int) {
+}
+
+func (g *Game) hotReload() {
+}
+
 func (p *Player) String() string {
 	return ""
 }
@@ -0,0 +1,206 @@
+//go:build !prod
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"time"
+
+	"github.com/creativeprojects/cavern/overlay"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// files watched by the hot reload
+var reloadPatterns = []string{"images/*.png", "sounds/*.ogg", "sounds/*.json", "levels/*.json", "animations/*.json", "shaders/*.kage", "themes/*.json"}
+
+var (
+	reloadStarted bool
+	reloadWatcher *watcher // nil when the asset folders are not on disk
+	reloadFiles   fs.FS    // the files on disk on top of the assets
+)
+
+// hotReload reloads the asset files changed on disk into the running game.
+// The files are watched in the resource pack directory, or in the current directory (the source code).
+func (g *Game) hotReload() {
+	if !reloadStarted {
+		reloadStarted = true
+		dir := "."
+		if info, err := os.Stat(resourcePack); err == nil && info.IsDir() {
+			dir = resourcePack
+		}
+		if _, err := os.Stat(path.Join(dir, "images")); err != nil {
+			log.Printf("hot reload disabled: no images folder in %q", dir)
+			return
+		}
+		onDisk := os.DirFS(dir)
+		reloadFiles = overlay.New(onDisk, assetFiles)
+		reloadWatcher = newWatcher(onDisk, reloadPatterns...)
+		log.Printf("hot reload: watching files in %q", dir)
+	}
+	if reloadWatcher == nil || int(g.timer)%HotReloadRate != 0 {
+		return
+	}
+
+	levelsChanged := false
+	animationsChanged := false
+	redraw := false
+	for _, filename := range reloadWatcher.Changes() {
+		var err error
+		switch path.Dir(filename) {
+		case "images":
+			err = reloadImage(filename)
+			redraw = true
+		case "sounds":
+			err = g.reloadSound(filename)
+		case "levels":
+			levelsChanged = true
+		case "animations":
+			animationsChanged = true
+		case "shaders":
+			err = g.reloadShader(filename)
+			redraw = true
+		case "themes":
+			levelsChanged = true
+		}
+		if err != nil {
+			log.Printf("hot reload: %v", err)
+			continue
+		}
+		log.Printf("hot reload: %s", filename)
+	}
+	if animationsChanged {
+		err := reloadAnimations()
+		if err != nil {
+			log.Printf("hot reload: %v", err)
+		}
+	}
+	if redraw {
+		g.level.Redraw()
+	}
+	if levelsChanged {
+		levels, err := loadLevels(reloadFiles)
+		if err != nil {
+			log.Printf("hot reload: %v", err)
+			return
+		}
+		loaded, err := loadThemes(reloadFiles, func(name string) *ebiten.Image {
+			return images[name]
+		})
+		if err != nil {
+			log.Printf("hot reload: %v", err)
+			return
+		}
+		LevelsDefinition = levels
+		themes = loaded
+		// the enemies and items stay where they are
+		g.level.Reload()
+	}
+}
+
+// reloadImage swaps the image in place in the atlas
+func reloadImage(filename string) error {
+	img, err := decodeImage(reloadFiles, filename)
+	if err != nil {
+		return err
+	}
+	name := assetName(filename)
+	err = atlas.Replace(name, img)
+	if err != nil {
+		// the image doesn't fit in the atlas anymore: only the sprites created from now on will use it
+		log.Printf("hot reload: %v", err)
+		images[name] = ebiten.NewImageFromImage(img)
+	}
+	return nil
+}
+
+// reloadAnimations loads all the animations again, and copies them in place:
+// the sprites playing an animation carry on with the new frames and durations
+func reloadAnimations() error {
+	reloaded, err := loadAnimations(reloadFiles)
+	if err != nil {
+		return err
+	}
+	for name, animation := range reloaded {
+		if existing, found := animations[name]; found {
+			existing.Reload(animation)
+			continue
+		}
+		animations[name] = animation
+	}
+	return nil
+}
+
+// reloadShader compiles the shader again and swaps it in the post processing chain
+func (g *Game) reloadShader(filename string) error {
+	shader, err := compileShader(reloadFiles, filename)
+	if err != nil {
+		return err
+	}
+	name := assetName(filename)
+	shaders[name] = shader
+	if pass := g.postProcess.Pass(name); pass != nil {
+		pass.Shader = shader
+	}
+	return nil
+}
+
+// reloadSound decodes the sound again: sounds are looked up by name each time they're played
+func (g *Game) reloadSound(filename string) error {
+	name := assetName(filename)
+	if path.Ext(filename) == ".json" {
+		if _, err := fs.Stat(reloadFiles, "sounds/"+name+".ogg"); err == nil {
+			return errors.New(filename + ": ignored as " + name + ".ogg takes precedence")
+		}
+	}
+	buffer, err := decodeSound(g.mixer.audioContext, reloadFiles, filename)
+	if err != nil {
+		return err
+	}
+	sounds[name] = buffer
+	return nil
+}
+
+// watcher polls the modification time of the files matching the patterns
+type watcher struct {
+	fsys     fs.FS
+	patterns []string
+	modTimes map[string]time.Time
+}
+
+// newWatcher creates a watcher. Only the files changed after it was created are reported
+func newWatcher(fsys fs.FS, patterns ...string) *watcher {
+	w := &watcher{
+		fsys:     fsys,
+		patterns: patterns,
+		modTimes: make(map[string]time.Time, 200),
+	}
+	w.Changes()
+	return w
+}
+
+// Changes returns the files created or modified since the last call
+func (w *watcher) Changes() []string {
+	changed := make([]string, 0)
+	for _, pattern := range w.patterns {
+		filenames, err := fs.Glob(w.fsys, pattern)
+		if err != nil {
+			continue
+		}
+		for _, filename := range filenames {
+			info, err := fs.Stat(w.fsys, filename)
+			if err != nil {
+				continue
+			}
+			if modTime, found := w.modTimes[filename]; found && modTime.Equal(info.ModTime()) {
+				continue
+			}
+			w.modTimes[filename] = info.ModTime()
+			changed = append(changed, filename)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,38 @@
+//go:build !prod
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcherChanges(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "images"), 0o755))
+	robot := filepath.Join(root, "images", "robot.png")
+	require.NoError(t, os.WriteFile(robot, []byte("robot"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "images", "notes.txt"), []byte("notes"), 0o644))
+
+	w := newWatcher(os.DirFS(root), "images/*.png")
+	assert.Empty(t, w.Changes())
+
+	// modified file
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(robot, later, later))
+	assert.Equal(t, []string{"images/robot.png"}, w.Changes())
+	assert.Empty(t, w.Changes())
+
+	// new file
+	require.NoError(t, os.WriteFile(filepath.Join(root, "images", "player.png"), []byte("player"), 0o644))
+	assert.Equal(t, []string{"images/player.png"}, w.Changes())
+
+	// not matching the pattern
+	require.NoError(t, os.Chtimes(filepath.Join(root, "images", "notes.txt"), later, later))
+	assert.Empty(t, w.Changes())
+}
@@ -3,75 +3,210 @@ package main
 import (
 	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"image"
+	"io"
 	"io/fs"
 	"path"
 	"strings"
 
 	_ "image/png"
 
+	"github.com/creativeprojects/cavern/i18n"
+	"github.com/creativeprojects/cavern/lib"
+	"github.com/creativeprojects/cavern/overlay"
+	"github.com/creativeprojects/cavern/synth"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"golang.org/x/image/font/gofont/gobold"
 )
 
-//go:embed images sounds music
+//go:embed images sounds music fonts lang levels animations shaders themes
 var embededFiles embed.FS
 
-func loadImages() (map[string]*ebiten.Image, error) {
-	imageNames, err := fs.Glob(embededFiles, "images/*.png")
+// resourcePack is the name of the resource pack loaded, if any
+var resourcePack string
+
+// assetFiles are the files all the assets are loaded from: the embedded files, with the resource pack on top (if any)
+var assetFiles fs.FS = embededFiles
+
+// loadResourcePack loads a directory or a zip file on top of the embedded files:
+// any file in the pack replaces the embedded file with the same name
+func loadResourcePack(name string) (io.Closer, error) {
+	pack, closer, err := overlay.OpenLayer(name)
+	if err != nil {
+		return nil, err
+	}
+	assetFiles = overlay.New(pack, embededFiles)
+	resourcePack = name
+	return closer, nil
+}
+
+// the TrueType font is also used by the web page
+//
+//go:embed wasm/destructobeambb_reg.ttf
+var trueTypeFont []byte
+
+// loadTextRenderer loads the TrueType font. It only contains ASCII characters:
+// accented characters come from the Go font, and everything else (like Japanese) from the fallback glyph sheet
+func loadTextRenderer() (*lib.TextRenderer, error) {
+	renderer, err := lib.NewTextRenderer(bytes.NewReader(trueTypeFont))
+	if err != nil {
+		return nil, err
+	}
+	err = renderer.AddFallback(bytes.NewReader(gobold.TTF))
+	if err != nil {
+		return nil, err
+	}
+	fallback, err := loadFallbackFace()
+	if err != nil {
+		return nil, err
+	}
+	renderer.SetFixedFallback(fallback)
+	return renderer, nil
+}
+
+// loadFallbackFace loads the glyphs of the messages missing from the TrueType fonts (see cmd/fallbackfont)
+func loadFallbackFace() (*lib.SheetFace, error) {
+	file, err := assetFiles.Open(fontFallbackSheet)
 	if err != nil {
 		return nil, err
 	}
-	imagesMap := make(map[string]*ebiten.Image, len(imageNames))
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fontFallbackSheet, err)
+	}
+	metrics, err := assetFiles.Open(fontFallbackMetrics)
+	if err != nil {
+		return nil, err
+	}
+	defer metrics.Close()
+	face, err := lib.NewSheetFace(img, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fontFallbackMetrics, err)
+	}
+	return face, nil
+}
+
+func loadMessages(language string) (*i18n.Catalogue, error) {
+	catalogue, err := i18n.Load(assetFiles, "lang/*.json", defaultLanguage)
+	if err != nil {
+		return nil, err
+	}
+	catalogue.SetLanguage(language)
+	return catalogue, nil
+}
+
+func loadFont() (*lib.BitmapFont, error) {
+	file, err := assetFiles.Open(fontSheet)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fontSheet, err)
+	}
+	metrics, err := assetFiles.Open(fontMetrics)
+	if err != nil {
+		return nil, err
+	}
+	defer metrics.Close()
+	font, err := lib.LoadBitmapFont(ebiten.NewImageFromImage(img), metrics)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fontMetrics, err)
+	}
+	return font, nil
+}
+
+// loadImages packs all the images into an atlas
+func loadImages() (*lib.Atlas, error) {
+	imageNames, err := fs.Glob(assetFiles, "images/*.png")
+	if err != nil {
+		return nil, err
+	}
+	decoded := make(map[string]image.Image, len(imageNames))
 	for _, imageName := range imageNames {
-		file, err := embededFiles.Open(imageName)
+		img, err := decodeImage(assetFiles, imageName)
 		if err != nil {
-			return imagesMap, fmt.Errorf("%s: %w", imageName, err)
+			return nil, err
 		}
-		defer file.Close()
-		img, _, err := image.Decode(file)
-		if err != nil {
-			return imagesMap, fmt.Errorf("%s: %w", imageName, err)
-		}
-		img2 := ebiten.NewImageFromImage(img)
-		if err != nil {
-			return imagesMap, fmt.Errorf("%s: %w", imageName, err)
-		}
-		imageName = path.Base(imageName)
-		imageName = strings.TrimSuffix(imageName, path.Ext(imageName))
-		imagesMap[imageName] = img2
+		decoded[assetName(imageName)] = img
 	}
-	return imagesMap, nil
+	return lib.NewAtlas(decoded, AtlasPageSize, AtlasPadding)
 }
 
 func loadSounds(context *audio.Context) (map[string][]byte, error) {
-	soundNames, err := fs.Glob(embededFiles, "sounds/*.ogg")
+	soundNames, err := fs.Glob(assetFiles, "sounds/*.ogg")
 	if err != nil {
 		return nil, err
 	}
-	soundsMap := make(map[string][]byte, len(soundNames))
-	for _, soundName := range soundNames {
-		// annoyingly, fs.File does not implement io.ReadSeeker,
-		// so we need to load it first and create a reader from the buffer
-		buffer, err := embededFiles.ReadFile(soundName)
-		if err != nil {
-			return soundsMap, fmt.Errorf("%s: %w", soundName, err)
+	// sound effects can also be synthesized from a parameter file
+	paramsNames, err := fs.Glob(assetFiles, "sounds/*.json")
+	if err != nil {
+		return nil, err
+	}
+	soundsMap := make(map[string][]byte, len(soundNames)+len(paramsNames))
+	for _, soundName := range append(soundNames, paramsNames...) {
+		if _, found := soundsMap[assetName(soundName)]; found {
+			// OGG file takes precedence
+			continue
 		}
-		reader := bytes.NewReader(buffer)
-		snd, err := vorbis.Decode(context, reader)
+		buffer, err := decodeSound(context, assetFiles, soundName)
 		if err != nil {
-			return soundsMap, fmt.Errorf("%s: %w", soundName, err)
+			return soundsMap, err
 		}
-		buf := make([]byte, snd.Length())
-		_, err = snd.Read(buf)
+		soundsMap[assetName(soundName)] = buffer
+	}
+	return soundsMap, nil
+}
+
+// decodeImage loads a PNG image
+func decodeImage(fsys fs.FS, filename string) (image.Image, error) {
+	file, err := fsys.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return img, nil
+}
+
+// decodeSound loads an OGG file, or synthesizes the sound from a parameter file (.json)
+func decodeSound(context *audio.Context, fsys fs.FS, filename string) ([]byte, error) {
+	// annoyingly, fs.File does not implement io.ReadSeeker,
+	// so we need to load it first and create a reader from the buffer
+	buffer, err := fs.ReadFile(fsys, filename)
+	if err != nil {
+		return nil, err
+	}
+	if path.Ext(filename) == ".json" {
+		params := synth.Params{}
+		err = json.Unmarshal(buffer, &params)
 		if err != nil {
-			return soundsMap, fmt.Errorf("%s: %w", soundName, err)
+			return nil, fmt.Errorf("%s: %w", filename, err)
 		}
-		soundName = path.Base(soundName)
-		soundName = strings.TrimSuffix(soundName, path.Ext(soundName))
-		soundsMap[soundName] = buf
+		return synth.Render(params, SampleRate), nil
 	}
-	return soundsMap, nil
+	snd, err := vorbis.Decode(context, bytes.NewReader(buffer))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	buf := make([]byte, snd.Length())
+	_, err = io.ReadFull(snd, buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return buf, nil
+}
+
+// assetName returns the name of an asset from its file name ("images/robot104.png" is "robot104")
+func assetName(filename string) string {
+	return strings.TrimSuffix(path.Base(filename), path.Ext(filename))
 }
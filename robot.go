@@ -16,14 +16,13 @@ const (
 	RobotAggressive
 )
 
+// robotHitbox is used by all the animations of the robots, relative to the bottom centre
+var robotHitbox = lib.Hitbox{X: -22, Y: -70, Width: 44, Height: 70}
+
 type Robot struct {
 	*Gravity
-	imagesLeft           [2][]*ebiten.Image
-	imagesRight          [2][]*ebiten.Image
-	imagesLeftFire       [2][]*ebiten.Image
-	imagesRightFire      [2][]*ebiten.Image
-	trapSounds           [][]byte
-	laserSounds          [][]byte
+	animWalk             [2][2]*lib.Animation // [robot type][direction]
+	animFire             [2][2]*lib.Animation
 	robotType            RobotType
 	alive                bool
 	directionX           float64
@@ -33,27 +32,17 @@ type Robot struct {
 }
 
 func NewRobot(level *Level) *Robot {
-	sprite := lib.NewSprite(lib.XCentre, lib.YBottom)
+	sprite := lib.NewSprite(lib.XCentre, lib.YBottom).SetHitbox(&robotHitbox)
 	return &Robot{
 		Gravity: NewGravity(level, sprite),
-		imagesLeft: [2][]*ebiten.Image{
-			{images["robot000"], images["robot001"], images["robot002"], images["robot003"], images["robot004"]},
-			{images["robot100"], images["robot101"], images["robot102"], images["robot103"], images["robot104"]},
-		},
-		imagesRight: [2][]*ebiten.Image{
-			{images["robot010"], images["robot011"], images["robot012"], images["robot013"], images["robot014"]},
-			{images["robot110"], images["robot111"], images["robot112"], images["robot113"], images["robot114"]},
-		},
-		imagesLeftFire: [2][]*ebiten.Image{
-			{images["robot005"], images["robot006"], images["robot007"]},
-			{images["robot105"], images["robot106"], images["robot107"]},
+		animWalk: [2][2]*lib.Animation{
+			{Animation(AnimationsRobotWalk[0][0]), Animation(AnimationsRobotWalk[0][1])},
+			{Animation(AnimationsRobotWalk[1][0]), Animation(AnimationsRobotWalk[1][1])},
 		},
-		imagesRightFire: [2][]*ebiten.Image{
-			{images["robot015"], images["robot016"], images["robot017"]},
-			{images["robot115"], images["robot116"], images["robot117"]},
+		animFire: [2][2]*lib.Animation{
+			{Animation(AnimationsRobotFire[0][0]), Animation(AnimationsRobotFire[0][1])},
+			{Animation(AnimationsRobotFire[1][0]), Animation(AnimationsRobotFire[1][1])},
 		},
-		trapSounds:  [][]byte{sounds["trap0"], sounds["trap1"], sounds["trap2"], sounds["trap3"]},
-		laserSounds: [][]byte{sounds["laser0"], sounds["laser1"], sounds["laser2"], sounds["laser3"]},
 	}
 }
 
@@ -67,7 +56,7 @@ func (r *Robot) Generate(robotType RobotType) *Robot {
 	r.fireTimer = 100
 	x := r.level.GetRobotSpawnX()
 	y := -30.0
-	r.Sprite.MoveTo(x, y)
+	r.Sprite.Teleport(x, y)
 	return r
 }
 
@@ -91,10 +80,9 @@ func (r *Robot) Update(game *Game) {
 		directions := []float64{-1, 1}
 		r.directionX = directions[rand.Intn(len(directions))]
 		r.changeDirectionTimer = randomInt(100, 251)
-		if r.directionX == -1 {
-			r.Sprite.Animate(r.imagesLeft[r.robotType-1], nil, 4, true)
-		} else {
-			r.Sprite.Animate(r.imagesRight[r.robotType-1], nil, 4, true)
+		if !r.firing() {
+			// otherwise the walk animation comes back once the bolt is released
+			r.Sprite.Play(r.animWalk[r.robotType-1][r.direction()])
 		}
 	}
 	r.Gravity.UpdateFall()
@@ -119,6 +107,7 @@ func (r *Robot) Update(game *Game) {
 					r.directionX = 1
 				}
 				r.fireTimer = 0
+				r.Sprite.Play(r.animFire[r.robotType-1][r.direction()])
 				break
 			}
 		}
@@ -132,40 +121,49 @@ func (r *Robot) Update(game *Game) {
 		}
 		if rand.Float64() < probability {
 			r.fireTimer = 1
-			game.RandomSoundEffect(r.laserSounds)
 			// change animation
-			if r.directionX == -1 {
-				r.Sprite.Animate(r.imagesLeftFire[r.robotType-1], nil, 4, false)
-			} else {
-				r.Sprite.Animate(r.imagesRightFire[r.robotType-1], nil, 4, false)
-			}
+			r.Sprite.Play(r.animFire[r.robotType-1][r.direction()])
 		}
-	} else if r.fireTimer == 8 {
-		// once the fire timer has been set to 0, it will count up - frame 8 of the animation is when the actual bolt is fired
-		game.Fire(r.directionX, r.X(lib.XCentre)+r.directionX*20, r.Y(lib.YCentre))
 	}
 	// am I colliding with an Orb? if so, become trapped in it
 	for _, orb := range game.orbs {
 		if orb.IsActive() && !orb.EnemyTrapped() && r.CollidePoint(orb.X(lib.XCentre), orb.Y(lib.YCentre)) {
 			r.alive = false
 			orb.TrapEnemy(r.robotType)
-			game.RandomSoundEffect(r.trapSounds)
+			game.particles.Burst(EffectTrap, r.X(lib.XCentre), r.Y(lib.YCentre))
 			// no need to go further
 			return
 		}
 	}
 
-	// change animation back to normal after firing
-	if r.fireTimer == 12 {
-		// put normal animation back
-		if r.directionX == -1 {
-			r.Sprite.Animate(r.imagesLeft[r.robotType-1], nil, 4, true)
-		} else {
-			r.Sprite.Animate(r.imagesRight[r.robotType-1], nil, 4, true)
+	r.Sprite.Update()
+	for _, event := range r.Sprite.Events() {
+		switch event {
+		case EventFire:
+			// the bolt is released on the frame of the firing animation with the fire event
+			game.Fire(r.directionX, r.X(lib.XCentre)+r.directionX*20, r.Y(lib.YCentre))
+		case lib.EventDone:
+			// put normal animation back after firing
+			r.Sprite.Play(r.animWalk[r.robotType-1][r.direction()])
+		default:
+			game.EventSound(event, r.X(lib.XCentre), r.Y(lib.YCentre))
 		}
 	}
+}
 
-	r.Sprite.Update()
+// firing returns true while the fire animation is playing: its fire event releases the bolt
+func (r *Robot) firing() bool {
+	playing := r.Sprite.Playing()
+	fire := r.animFire[r.robotType-1]
+	return (playing == fire[0] || playing == fire[1]) && !r.Sprite.IsFinished()
+}
+
+// direction returns the index of the animations facing the current direction: 0 for left, 1 for right
+func (r *Robot) direction() int {
+	if r.directionX == -1 {
+		return 0
+	}
+	return 1
 }
 
 func (r *Robot) Draw(screen *ebiten.Image) {
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/creativeprojects/cavern/lib"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Action is a player action that can be bound to a key
+type Action string
+
+// Player actions
+const (
+	ActionLeft  Action = "left"
+	ActionRight Action = "right"
+	ActionJump  Action = "jump"
+	ActionBlow  Action = "blow"
+	ActionPause Action = "pause"
+	ActionMute  Action = "mute"
+)
+
+// Actions lists all the actions in the order they're displayed in the options screen
+var Actions = []Action{ActionLeft, ActionRight, ActionJump, ActionBlow, ActionPause, ActionMute}
+
+// Difficulty is the starting difficulty of a new game
+type Difficulty int
+
+// Difficulty levels
+const (
+	DifficultyEasy Difficulty = iota
+	DifficultyNormal
+	DifficultyHard
+)
+
+// String representation of Difficulty
+func (d Difficulty) String() string {
+	switch d {
+	case DifficultyEasy:
+		return "EASY"
+	case DifficultyHard:
+		return "HARD"
+	default:
+		return "NORMAL"
+	}
+}
+
+// StartLives returns the number of extra lives the player starts with
+func (d Difficulty) StartLives() int {
+	switch d {
+	case DifficultyEasy:
+		return PlayerStartLives + 1
+	case DifficultyHard:
+		return PlayerStartLives - 1
+	default:
+		return PlayerStartLives
+	}
+}
+
+// FireFactor returns the multiplier applied to the probability of robots firing a bolt
+func (d Difficulty) FireFactor() float64 {
+	switch d {
+	case DifficultyEasy:
+		return 0.5
+	case DifficultyHard:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Settings contains the user preferences, saved between sessions
+type Settings struct {
+	MusicVolume   int                   `json:"music_volume"`   // from 0 to MaxVolume
+	EffectsVolume int                   `json:"effects_volume"` // from 0 to MaxVolume
+	Keys          map[Action]ebiten.Key `json:"keys"`
+	Fullscreen    bool                  `json:"fullscreen"`
+	WindowScale   int                   `json:"window_scale"`
+	ScaleMode     lib.ScaleMode         `json:"scale_mode"`
+	Interpolation bool                  `json:"interpolation"` // draw the sprites between two ticks, for smooth movements
+	Difficulty    Difficulty            `json:"difficulty"`
+	Language      string                `json:"language"` // language code, the default language when empty
+	Scanlines     bool                  `json:"scanlines"`
+	Curvature     bool                  `json:"curvature"`
+	Bloom         bool                  `json:"bloom"`
+	ColourBlind   ColourBlindMode       `json:"colour_blind"`
+}
+
+// NewSettings returns the default settings
+func NewSettings() *Settings {
+	return &Settings{
+		MusicVolume:   DefaultMusicVolume,
+		EffectsVolume: DefaultEffectsVolume,
+		Keys: map[Action]ebiten.Key{
+			ActionLeft:  ebiten.KeyLeft,
+			ActionRight: ebiten.KeyRight,
+			ActionJump:  ebiten.KeyUp,
+			ActionBlow:  ebiten.KeySpace,
+			ActionPause: ebiten.KeyP,
+			ActionMute:  ebiten.KeyM,
+		},
+		Fullscreen:    false,
+		WindowScale:   1,
+		Difficulty:    DifficultyNormal,
+		Interpolation: true,
+	}
+}
+
+// LoadSettings loads the settings saved from a previous session.
+// Default settings are returned when nothing was saved, along with any error reading them.
+func LoadSettings() (*Settings, error) {
+	settings := NewSettings()
+	data, err := loadSettingsData()
+	if err != nil || len(data) == 0 {
+		return settings, err
+	}
+	err = json.Unmarshal(data, settings)
+	if err != nil {
+		return NewSettings(), err
+	}
+	settings.validate()
+	return settings, nil
+}
+
+// Save the settings for the next session
+func (s *Settings) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return saveSettingsData(data)
+}
+
+// Key returns the key bound to the action
+func (s *Settings) Key(action Action) ebiten.Key {
+	return s.Keys[action]
+}
+
+// BindKey binds the key to the action. The action which was using the key gets the previous key of this action:
+// a key is never bound to two actions.
+func (s *Settings) BindKey(action Action, key ebiten.Key) {
+	for other, otherKey := range s.Keys {
+		if other != action && otherKey == key {
+			s.Keys[other] = s.Keys[action]
+		}
+	}
+	s.Keys[action] = key
+}
+
+// Volume returns the volume (between 0 and 1) from a setting value (between 0 and MaxVolume)
+func Volume(value int) float64 {
+	return float64(value) / MaxVolume
+}
+
+// validate puts back any missing or out of range value to its default
+func (s *Settings) validate() {
+	defaults := NewSettings()
+	if s.MusicVolume < 0 || s.MusicVolume > MaxVolume {
+		s.MusicVolume = defaults.MusicVolume
+	}
+	if s.EffectsVolume < 0 || s.EffectsVolume > MaxVolume {
+		s.EffectsVolume = defaults.EffectsVolume
+	}
+	if s.Keys == nil {
+		s.Keys = defaults.Keys
+	}
+	bound := make(map[ebiten.Key]bool, len(Actions))
+	for _, action := range Actions {
+		key, found := s.Keys[action]
+		if !found {
+			key = defaults.Keys[action]
+			s.Keys[action] = key
+		}
+		if bound[key] {
+			// a key bound to two actions: the one checked first would always win
+			s.Keys = defaults.Keys
+			break
+		}
+		bound[key] = true
+	}
+	if s.WindowScale < 1 || s.WindowScale > MaxWindowScale {
+		s.WindowScale = defaults.WindowScale
+	}
+	if s.Difficulty < DifficultyEasy || s.Difficulty > DifficultyHard {
+		s.Difficulty = defaults.Difficulty
+	}
+	if s.ScaleMode < lib.ScaleSmooth || s.ScaleMode > lib.ScalePixelPerfect {
+		s.ScaleMode = defaults.ScaleMode
+	}
+	if s.ColourBlind < ColourBlindOff || s.ColourBlind > ColourBlindTritanopia {
+		s.ColourBlind = defaults.ColourBlind
+	}
+}
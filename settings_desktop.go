@@ -0,0 +1,46 @@
+//go:build !js
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const settingsFile = "settings.json"
+
+// settingsPath returns the settings file inside the user configuration directory
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cavern", settingsFile), nil
+}
+
+func loadSettingsData() ([]byte, error) {
+	filename, err := settingsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		// nothing was saved yet
+		return nil, nil
+	}
+	return data, err
+}
+
+func saveSettingsData(data []byte) error {
+	filename, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(filename), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o644)
+}
@@ -0,0 +1,28 @@
+//go:build js
+
+package main
+
+import "syscall/js"
+
+const settingsKey = "cavern.settings"
+
+func loadSettingsData() ([]byte, error) {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return nil, nil
+	}
+	item := storage.Call("getItem", settingsKey)
+	if item.IsNull() || item.IsUndefined() {
+		return nil, nil
+	}
+	return []byte(item.String()), nil
+}
+
+func saveSettingsData(data []byte) error {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return nil
+	}
+	storage.Call("setItem", settingsKey, string(data))
+	return nil
+}
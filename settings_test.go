@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/creativeprojects/cavern/lib"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsRoundTrip(t *testing.T) {
+	settings := NewSettings()
+	settings.MusicVolume = 40
+	settings.Keys[ActionBlow] = ebiten.KeyX
+	settings.Difficulty = DifficultyHard
+	settings.ScaleMode = lib.ScalePixelPerfect
+	settings.Scanlines = true
+	settings.ColourBlind = ColourBlindDeuteranopia
+
+	data, err := json.Marshal(settings)
+	require.NoError(t, err)
+
+	loaded := NewSettings()
+	require.NoError(t, json.Unmarshal(data, loaded))
+	loaded.validate()
+	assert.Equal(t, settings, loaded)
+}
+
+func TestSettingsValidate(t *testing.T) {
+	settings := &Settings{
+		MusicVolume:   -1,
+		EffectsVolume: MaxVolume + 1,
+		Keys:          map[Action]ebiten.Key{ActionJump: ebiten.KeyW},
+		WindowScale:   0,
+		Difficulty:    Difficulty(10),
+		ScaleMode:     lib.ScaleMode(5),
+		ColourBlind:   ColourBlindMode(-1),
+	}
+	settings.validate()
+
+	defaults := NewSettings()
+	assert.Equal(t, defaults.MusicVolume, settings.MusicVolume)
+	assert.Equal(t, defaults.EffectsVolume, settings.EffectsVolume)
+	assert.Equal(t, ebiten.KeyW, settings.Key(ActionJump))
+	assert.Equal(t, ebiten.KeyLeft, settings.Key(ActionLeft))
+	assert.Equal(t, defaults.WindowScale, settings.WindowScale)
+	assert.Equal(t, DifficultyNormal, settings.Difficulty)
+	assert.Equal(t, lib.ScaleSmooth, settings.ScaleMode)
+	assert.Equal(t, ColourBlindOff, settings.ColourBlind)
+}
+
+func TestSettingsValidateKeys(t *testing.T) {
+	testData := []struct {
+		name string
+		keys map[Action]ebiten.Key
+	}{
+		{"conflict", map[Action]ebiten.Key{ActionLeft: ebiten.KeyA, ActionRight: ebiten.KeyD, ActionJump: ebiten.KeySpace, ActionBlow: ebiten.KeySpace, ActionPause: ebiten.KeyP, ActionMute: ebiten.KeyM}},
+		{"conflict with a missing key", map[Action]ebiten.Key{ActionJump: ebiten.KeyM}},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			settings := NewSettings()
+			settings.Keys = testItem.keys
+			settings.validate()
+			assert.Equal(t, NewSettings().Keys, settings.Keys)
+		})
+	}
+}
+
+func TestSettingsBindKey(t *testing.T) {
+	settings := NewSettings()
+	settings.BindKey(ActionJump, ebiten.KeyW)
+	assert.Equal(t, ebiten.KeyW, settings.Key(ActionJump))
+
+	// the keys of jump and blow are swapped
+	settings.BindKey(ActionJump, ebiten.KeySpace)
+	assert.Equal(t, ebiten.KeySpace, settings.Key(ActionJump))
+	assert.Equal(t, ebiten.KeyW, settings.Key(ActionBlow))
+
+	settings.BindKey(ActionJump, ebiten.KeySpace)
+	assert.Equal(t, ebiten.KeySpace, settings.Key(ActionJump))
+	assert.Equal(t, ebiten.KeyW, settings.Key(ActionBlow))
+}
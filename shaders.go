@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/creativeprojects/cavern/lib"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Shaders in the shaders folder
+const (
+	ShaderPalette     = "palette" // colours of the level themes
+	ShaderColourBlind = "colourblind"
+	ShaderBloom       = "bloom"
+	ShaderScanlines   = "scanlines"
+	ShaderCurvature   = "curvature"
+)
+
+// allShaders lists the shaders used by the game
+var allShaders = []string{ShaderPalette, ShaderColourBlind, ShaderBloom, ShaderScanlines, ShaderCurvature}
+
+// ColourBlindMode is the type of colour blindness the colours are remapped for
+type ColourBlindMode int
+
+// Colour blindness
+const (
+	ColourBlindOff ColourBlindMode = iota
+	ColourBlindProtanopia
+	ColourBlindDeuteranopia
+	ColourBlindTritanopia
+)
+
+// String representation of ColourBlindMode
+func (m ColourBlindMode) String() string {
+	switch m {
+	case ColourBlindProtanopia:
+		return "protanopia"
+	case ColourBlindDeuteranopia:
+		return "deuteranopia"
+	case ColourBlindTritanopia:
+		return "tritanopia"
+	default:
+		return "off"
+	}
+}
+
+// simulation returns the matrix simulating the colour blindness in the LMS colour space
+func (m ColourBlindMode) simulation() []float32 {
+	switch m {
+	case ColourBlindProtanopia:
+		return []float32{
+			0, 2.02344, -2.52581,
+			0, 1, 0,
+			0, 0, 1,
+		}
+	case ColourBlindDeuteranopia:
+		return []float32{
+			1, 0, 0,
+			0.494207, 0, 1.24827,
+			0, 0, 1,
+		}
+	case ColourBlindTritanopia:
+		return []float32{
+			1, 0, 0,
+			0, 1, 0,
+			-0.395913, 0.801109, 0,
+		}
+	default:
+		return []float32{
+			1, 0, 0,
+			0, 1, 0,
+			0, 0, 1,
+		}
+	}
+}
+
+// loadShaders compiles all the post processing shaders (shaders/*.kage)
+func loadShaders(fsys fs.FS) (map[string]*ebiten.Shader, error) {
+	filenames, err := fs.Glob(fsys, "shaders/*.kage")
+	if err != nil {
+		return nil, err
+	}
+	loaded := make(map[string]*ebiten.Shader, len(filenames))
+	for _, filename := range filenames {
+		shader, err := compileShader(fsys, filename)
+		if err != nil {
+			return nil, err
+		}
+		loaded[assetName(filename)] = shader
+	}
+	return loaded, nil
+}
+
+func compileShader(fsys fs.FS, filename string) (*ebiten.Shader, error) {
+	source, err := fs.ReadFile(fsys, filename)
+	if err != nil {
+		return nil, err
+	}
+	shader, err := ebiten.NewShader(source)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return shader, nil
+}
+
+// newPostProcess creates the chain of shaders applied to the whole screen
+func newPostProcess() *lib.PostProcess {
+	postProcess := lib.NewPostProcess()
+	postProcess.Add(ShaderColourBlind, shaders[ShaderColourBlind], map[string]any{
+		"Simulation": ColourBlindOff.simulation(),
+	})
+	postProcess.Add(ShaderBloom, shaders[ShaderBloom], map[string]any{
+		"Threshold": 0.6,
+		"Intensity": 1.5,
+		"Spread":    2.0,
+	})
+	postProcess.Add(ShaderScanlines, shaders[ShaderScanlines], map[string]any{
+		"Intensity": 0.25,
+	})
+	postProcess.Add(ShaderCurvature, shaders[ShaderCurvature], map[string]any{
+		"Amount":   0.03,
+		"Vignette": 0.4,
+	})
+	return postProcess
+}
+
+// applyPostProcess enables the shaders selected in the settings
+func (g *Game) applyPostProcess() {
+	g.postProcess.Pass(ShaderColourBlind).Enabled = g.settings.ColourBlind != ColourBlindOff
+	g.postProcess.Pass(ShaderColourBlind).Uniforms["Simulation"] = g.settings.ColourBlind.simulation()
+	g.postProcess.Pass(ShaderBloom).Enabled = g.settings.Bloom
+	g.postProcess.Pass(ShaderScanlines).Enabled = g.settings.Scanlines
+	g.postProcess.Pass(ShaderCurvature).Enabled = g.settings.Curvature
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShadersCompile(t *testing.T) {
+	loaded, err := loadShaders(embededFiles)
+	require.NoError(t, err)
+	for _, name := range allShaders {
+		assert.Contains(t, loaded, name)
+	}
+}
+
+func TestColourBlindSimulation(t *testing.T) {
+	for mode := ColourBlindOff; mode <= ColourBlindTritanopia; mode++ {
+		assert.Len(t, mode.simulation(), 9, mode.String())
+	}
+}
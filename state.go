@@ -1,6 +1,6 @@
 package main
 
-// GameState is menu / playing / paused / game over
+// GameState is menu / playing / paused / game over / options
 type GameState int
 
 // Current state
@@ -9,4 +9,5 @@ const (
 	StatePlaying
 	StatePaused
 	StateGameOver
+	StateOptions
 )
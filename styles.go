@@ -0,0 +1,89 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/creativeprojects/cavern/lib"
+)
+
+// background of the game over screen, drawn on top of the game
+var gameOverBackground = color.RGBA{0x20, 0x30, 0x40, 0xa0}
+
+// Text styles used with the TrueType font
+var (
+	menuStyle = &lib.TextStyle{
+		Size:          24,
+		Colour:        color.RGBA{0xb0, 0xe0, 0xff, 0xff},
+		Outline:       2,
+		OutlineColour: color.RGBA{0x10, 0x20, 0x40, 0xff},
+		Shadow:        3,
+	}
+	menuSelectedStyle = &lib.TextStyle{
+		Size:          24,
+		Colour:        color.RGBA{0xff, 0xe0, 0x40, 0xff},
+		Outline:       2,
+		OutlineColour: color.RGBA{0x40, 0x20, 0x00, 0xff},
+		Shadow:        3,
+	}
+	menuValueStyle = &lib.TextStyle{
+		Size:          24,
+		Colour:        color.White,
+		Align:         lib.XRight,
+		Outline:       2,
+		OutlineColour: color.RGBA{0x10, 0x20, 0x40, 0xff},
+		Shadow:        3,
+	}
+	titleStyle = &lib.TextStyle{
+		Size:          48,
+		Colour:        color.RGBA{0xff, 0xe0, 0x40, 0xff},
+		Align:         lib.XCentre,
+		Outline:       3,
+		OutlineColour: color.RGBA{0x40, 0x20, 0x00, 0xff},
+		Shadow:        4,
+	}
+	gameOverStyle = &lib.TextStyle{
+		Size:          72,
+		Colour:        color.RGBA{0xe0, 0x30, 0x20, 0xff},
+		Align:         lib.XCentre,
+		Outline:       4,
+		OutlineColour: color.RGBA{0x30, 0x00, 0x00, 0xff},
+		Shadow:        5,
+	}
+	pressSpaceStyle = &lib.TextStyle{
+		Size:          32,
+		Colour:        color.RGBA{0x60, 0xe0, 0xff, 0xff},
+		Align:         lib.XCentre,
+		Outline:       3,
+		OutlineColour: color.RGBA{0x00, 0x20, 0x40, 0xff},
+		Shadow:        4,
+	}
+	bannerStyle = &lib.TextStyle{
+		Size:          22,
+		Colour:        color.White,
+		Align:         lib.XCentre,
+		Outline:       2,
+		OutlineColour: color.RGBA{0x20, 0x10, 0x40, 0xff},
+	}
+	hudStyle = &lib.TextStyle{
+		Size:          18,
+		Colour:        color.White,
+		Align:         lib.XCentre,
+		Outline:       2,
+		OutlineColour: color.Black,
+	}
+	hudRightStyle = &lib.TextStyle{
+		Size:          18,
+		Colour:        color.White,
+		Align:         lib.XRight,
+		Outline:       2,
+		OutlineColour: color.Black,
+	}
+	popupStyle = &lib.TextStyle{
+		Size:          20,
+		Colour:        color.RGBA{0xff, 0xff, 0xa0, 0xff},
+		Align:         lib.XCentre,
+		Outline:       2,
+		OutlineColour: color.RGBA{0x60, 0x30, 0x00, 0xff},
+		Shadow:        2,
+	}
+)
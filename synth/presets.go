@@ -0,0 +1,45 @@
+package synth
+
+// Presets of sound effects, which can be used as a starting point for a parameter file
+var Presets = map[string]Params{
+	"jump": {
+		Waveform:  Square,
+		Frequency: 300,
+		Slide:     1800,
+		DutyCycle: 0.3,
+		Sustain:   0.08,
+		Decay:     0.12,
+		Volume:    0.5,
+	},
+	"laser": {
+		Waveform:     Sawtooth,
+		Frequency:    1200,
+		Slide:        -5000,
+		MinFrequency: 150,
+		Sustain:      0.05,
+		Punch:        0.3,
+		Decay:        0.15,
+		Volume:       0.4,
+	},
+	"pop": {
+		Waveform:  Noise,
+		Frequency: 2000,
+		Slide:     -6000,
+		Sustain:   0.02,
+		Punch:     0.6,
+		Decay:     0.12,
+		Volume:    0.6,
+	},
+	"pickup": {
+		Waveform:     Square,
+		Frequency:    900,
+		Slide:        600,
+		DutyCycle:    0.5,
+		VibratoDepth: 0.05,
+		VibratoSpeed: 20,
+		Sustain:      0.06,
+		Punch:        0.4,
+		Decay:        0.2,
+		Volume:       0.4,
+	},
+}
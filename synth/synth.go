@@ -0,0 +1,166 @@
+// Package synth generates retro sound effects from a small set of parameters, in the spirit of sfxr.
+package synth
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// SampleRate is the sample rate of the game, in Hz
+const SampleRate = 44100
+
+// Waveform is the shape of the oscillator
+type Waveform int
+
+// Waveforms
+const (
+	Square Waveform = iota
+	Sawtooth
+	Sine
+	Triangle
+	Noise
+)
+
+var waveformNames = []string{"square", "sawtooth", "sine", "triangle", "noise"}
+
+// String representation of Waveform
+func (w Waveform) String() string {
+	if w < 0 || int(w) >= len(waveformNames) {
+		return fmt.Sprintf("waveform(%d)", int(w))
+	}
+	return waveformNames[w]
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (w Waveform) MarshalText() ([]byte, error) {
+	if w < 0 || int(w) >= len(waveformNames) {
+		return nil, fmt.Errorf("invalid waveform %d", int(w))
+	}
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (w *Waveform) UnmarshalText(text []byte) error {
+	name := strings.ToLower(string(text))
+	for i, waveformName := range waveformNames {
+		if name == waveformName {
+			*w = Waveform(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown waveform %q", string(text))
+}
+
+// Params describes a sound effect. Durations are in seconds and frequencies in Hz.
+type Params struct {
+	Waveform     Waveform `json:"waveform"`
+	Frequency    float64  `json:"frequency"`     // starting frequency
+	Slide        float64  `json:"slide"`         // frequency change per second (negative to slide down)
+	MinFrequency float64  `json:"min_frequency"` // the sound is cut when sliding under this frequency
+	DutyCycle    float64  `json:"duty_cycle"`    // square wave only: ratio of the period spent high (0 to 1, 0.5 when zero)
+	VibratoDepth float64  `json:"vibrato_depth"` // ratio of the frequency (0 to 1)
+	VibratoSpeed float64  `json:"vibrato_speed"` // vibrato frequency
+	Noise        float64  `json:"noise"`         // amount of noise mixed into the waveform (0 to 1)
+	Attack       float64  `json:"attack"`
+	Sustain      float64  `json:"sustain"`
+	Punch        float64  `json:"punch"` // extra volume at the start of the sustain (0 to 1)
+	Decay        float64  `json:"decay"`
+	Volume       float64  `json:"volume"` // 0 to 1 (1 when zero)
+}
+
+// Duration returns the length of the sound effect in seconds
+func (p Params) Duration() float64 {
+	return p.Attack + p.Sustain + p.Decay
+}
+
+// Render generates the sound effect as 16 bits little endian stereo PCM at the sample rate
+func Render(p Params, sampleRate int) []byte {
+	samples := int(p.Duration() * float64(sampleRate))
+	buffer := make([]byte, 0, samples*4)
+
+	volume := p.Volume
+	if volume == 0 {
+		volume = 1
+	}
+	duty := p.DutyCycle
+	if duty <= 0 || duty >= 1 {
+		duty = 0.5
+	}
+	// always generate the same noise for the same parameters
+	random := rand.New(rand.NewSource(1))
+	noise := 0.0
+	phase := 0.0
+	frequency := p.Frequency
+
+	for i := 0; i < samples; i++ {
+		t := float64(i) / float64(sampleRate)
+		if p.MinFrequency > 0 && frequency < p.MinFrequency {
+			break
+		}
+		current := frequency
+		if p.VibratoDepth > 0 {
+			current *= 1 + p.VibratoDepth*math.Sin(2*math.Pi*p.VibratoSpeed*t)
+		}
+		previous := phase
+		phase = math.Mod(phase+current/float64(sampleRate), 1)
+		if phase < previous || i == 0 {
+			// new period: the noise only changes once per period so it follows the pitch
+			noise = random.Float64()*2 - 1
+		}
+
+		value := oscillator(p.Waveform, phase, duty, noise)
+		if p.Noise > 0 && p.Waveform != Noise {
+			value = value*(1-p.Noise) + (random.Float64()*2-1)*p.Noise
+		}
+		value *= envelope(p, t) * volume
+
+		sample := int16(math.Max(-1, math.Min(1, value)) * math.MaxInt16)
+		// same sample on both channels
+		buffer = append(buffer, byte(sample), byte(sample>>8), byte(sample), byte(sample>>8))
+
+		frequency += p.Slide / float64(sampleRate)
+		if frequency < 0 {
+			frequency = 0
+		}
+	}
+	return buffer
+}
+
+// oscillator returns the value (-1 to 1) of the waveform at the phase (0 to 1)
+func oscillator(waveform Waveform, phase, duty, noise float64) float64 {
+	switch waveform {
+	case Sawtooth:
+		return 1 - phase*2
+	case Sine:
+		return math.Sin(2 * math.Pi * phase)
+	case Triangle:
+		if phase < 0.5 {
+			return phase*4 - 1
+		}
+		return 3 - phase*4
+	case Noise:
+		return noise
+	default:
+		if phase < duty {
+			return 1
+		}
+		return -1
+	}
+}
+
+// envelope returns the volume (0 to 1+punch) at time t
+func envelope(p Params, t float64) float64 {
+	switch {
+	case t < p.Attack:
+		return t / p.Attack
+	case t < p.Attack+p.Sustain:
+		// punch fades out during the sustain
+		return 1 + p.Punch*(1-(t-p.Attack)/p.Sustain)
+	case p.Decay > 0:
+		return math.Max(0, 1-(t-p.Attack-p.Sustain)/p.Decay)
+	default:
+		return 0
+	}
+}
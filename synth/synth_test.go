@@ -0,0 +1,89 @@
+package synth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderLength(t *testing.T) {
+	for name, params := range Presets {
+		t.Run(name, func(t *testing.T) {
+			pcm := Render(params, SampleRate)
+			assert.NotEmpty(t, pcm)
+			assert.Zero(t, len(pcm)%4, "stereo 16 bits frames")
+			assert.LessOrEqual(t, len(pcm), int(params.Duration()*SampleRate)*4)
+		})
+	}
+}
+
+func TestRenderIsDeterministic(t *testing.T) {
+	params := Presets["pop"]
+	assert.Equal(t, Render(params, SampleRate), Render(params, SampleRate))
+}
+
+func TestRenderStopsUnderMinFrequency(t *testing.T) {
+	params := Params{
+		Waveform:     Square,
+		Frequency:    1000,
+		Slide:        -1000,
+		MinFrequency: 500,
+		Sustain:      1,
+	}
+	pcm := Render(params, SampleRate)
+	// it takes half a second to slide from 1000Hz to 500Hz
+	assert.InDelta(t, SampleRate/2*4, len(pcm), 8)
+}
+
+func TestRenderEnvelope(t *testing.T) {
+	params := Params{
+		Waveform:  Square,
+		Frequency: 100,
+		DutyCycle: 0.99,
+		Attack:    0.5,
+		Sustain:   0.5,
+	}
+	pcm := Render(params, 100)
+	require.Len(t, pcm, 400)
+	sample := func(i int) int16 {
+		return int16(binary.LittleEndian.Uint16(pcm[i*4:]))
+	}
+	// silent at the start, half way through the attack, then full volume
+	assert.Equal(t, int16(0), sample(0))
+	assert.InDelta(t, 32767/2, sample(25), 1)
+	assert.Equal(t, int16(32767), sample(75))
+	// both channels are the same
+	assert.Equal(t, pcm[100:102], pcm[102:104])
+}
+
+func TestWaveformText(t *testing.T) {
+	data, err := json.Marshal(Params{Waveform: Triangle})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"waveform":"triangle"`)
+
+	params := Params{}
+	require.NoError(t, json.Unmarshal([]byte(`{"waveform":"Noise"}`), &params))
+	assert.Equal(t, Noise, params.Waveform)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"waveform":"organ"}`), &params))
+}
+
+func TestWriteWAV(t *testing.T) {
+	pcm := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	buffer := &bytes.Buffer{}
+	require.NoError(t, WriteWAV(buffer, pcm, SampleRate))
+
+	data := buffer.Bytes()
+	require.Len(t, data, 44+len(pcm))
+	assert.Equal(t, "RIFF", string(data[0:4]))
+	assert.Equal(t, uint32(36+len(pcm)), binary.LittleEndian.Uint32(data[4:]))
+	assert.Equal(t, "WAVEfmt ", string(data[8:16]))
+	assert.Equal(t, uint16(2), binary.LittleEndian.Uint16(data[22:]))
+	assert.Equal(t, uint32(SampleRate), binary.LittleEndian.Uint32(data[24:]))
+	assert.Equal(t, "data", string(data[36:40]))
+	assert.Equal(t, pcm, data[44:])
+}
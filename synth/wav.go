@@ -0,0 +1,38 @@
+package synth
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// WriteWAV writes the 16 bits stereo PCM data in a WAV container
+func WriteWAV(w io.Writer, pcm []byte, sampleRate int) error {
+	const (
+		channels      = 2
+		bitsPerSample = 16
+	)
+	blockAlign := channels * bitsPerSample / 8
+	header := []interface{}{
+		[4]byte{'R', 'I', 'F', 'F'},
+		uint32(36 + len(pcm)),
+		[4]byte{'W', 'A', 'V', 'E'},
+		[4]byte{'f', 'm', 't', ' '},
+		uint32(16), // size of the format chunk
+		uint16(1),  // PCM
+		uint16(channels),
+		uint32(sampleRate),
+		uint32(sampleRate * blockAlign),
+		uint16(blockAlign),
+		uint16(bitsPerSample),
+		[4]byte{'d', 'a', 't', 'a'},
+		uint32(len(pcm)),
+	}
+	for _, field := range header {
+		err := binary.Write(w, binary.LittleEndian, field)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(pcm)
+	return err
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// themePresets are the themes of the levels without a theme, one after the other
+var themePresets = []string{"blue", "red", "green", "grey"}
+
+// Colour is written "#rrggbb" in the data files
+type Colour color.NRGBA
+
+// UnmarshalText decodes a colour from "#rrggbb"
+func (c *Colour) UnmarshalText(text []byte) error {
+	if len(text) != 7 || text[0] != '#' {
+		return fmt.Errorf("invalid colour %q: expected #rrggbb", text)
+	}
+	value, err := strconv.ParseUint(string(text[1:]), 16, 32)
+	if err != nil {
+		return fmt.Errorf("invalid colour %q: expected #rrggbb", text)
+	}
+	*c = Colour{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xff}
+	return nil
+}
+
+// MarshalText encodes a colour to "#rrggbb"
+func (c Colour) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)), nil
+}
+
+// vec3 returns the colour as a shader uniform
+func (c Colour) vec3() []float32 {
+	return []float32{float32(c.R) / 0xff, float32(c.G) / 0xff, float32(c.B) / 0xff}
+}
+
+// Theme is the colours of a level. The background and block patterns are recoloured by the palette shader:
+// the dark parts of the patterns take the background colours, and the bright parts the block and highlight colours.
+// A theme without colours draws the patterns with their own colours.
+type Theme struct {
+	Background        [2]Colour `json:"background"` // from the shadows to the lit parts of the background
+	Block             Colour    `json:"block"`
+	Highlight         Colour    `json:"highlight"`
+	BackgroundPattern ImageName `json:"background_pattern"` // ImagesBg[0] when empty
+	BlockPattern      ImageName `json:"block_pattern"`      // ImagesBlock[0] when empty
+}
+
+// colours returns the number of colours set in the theme file
+func (t *Theme) colours() int {
+	count := 0
+	for _, colour := range []Colour{t.Background[0], t.Background[1], t.Block, t.Highlight} {
+		// the colours read from the file are opaque
+		if colour.A > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// recoloured returns true when the patterns go through the palette shader
+func (t *Theme) recoloured() bool {
+	return t.colours() > 0
+}
+
+// uniforms of the palette shader
+func (t *Theme) uniforms() map[string]any {
+	return map[string]any{
+		"Shadow":    t.Background[0].vec3(),
+		"Dark":      t.Background[1].vec3(),
+		"Base":      t.Block.vec3(),
+		"Highlight": t.Highlight.vec3(),
+	}
+}
+
+// loadThemes loads all the theme files (themes/*.json).
+// The patterns are the names of the images already loaded.
+func loadThemes(fsys fs.FS, lookup func(string) *ebiten.Image) (map[string]*Theme, error) {
+	filenames, err := fs.Glob(fsys, "themes/*.json")
+	if err != nil {
+		return nil, err
+	}
+	loaded := make(map[string]*Theme, len(themePresets))
+	for _, filename := range filenames {
+		list, err := loadThemeFile(fsys, filename, lookup)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		for name, theme := range list {
+			if _, found := loaded[name]; found {
+				return nil, fmt.Errorf("%s: theme %q is already defined in another file", filename, name)
+			}
+			loaded[name] = theme
+		}
+	}
+	return loaded, nil
+}
+
+func loadThemeFile(fsys fs.FS, filename string, lookup func(string) *ebiten.Image) (map[string]*Theme, error) {
+	file, err := fsys.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list := make(map[string]*Theme)
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&list)
+	if err != nil {
+		return nil, err
+	}
+	for name, theme := range list {
+		if colours := theme.colours(); colours > 0 && colours < 4 {
+			return nil, fmt.Errorf("theme %q: %d colours out of 4, remove them all to keep the colours of the patterns", name, colours)
+		}
+		if theme.BackgroundPattern == "" {
+			theme.BackgroundPattern = ImagesBg[0]
+		}
+		if theme.BlockPattern == "" {
+			theme.BlockPattern = ImagesBlock[0]
+		}
+		for _, pattern := range []ImageName{theme.BackgroundPattern, theme.BlockPattern} {
+			if lookup(string(pattern)) == nil {
+				return nil, fmt.Errorf("theme %q: image %q not found", name, pattern)
+			}
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// imageLookup finds the images of the images folder
+func imageLookup(name string) *ebiten.Image {
+	if _, err := fs.Stat(embededFiles, "images/"+name+".png"); err != nil {
+		return nil
+	}
+	return new(ebiten.Image)
+}
+
+func TestLoadEmbeddedThemes(t *testing.T) {
+	loaded, err := loadThemes(embededFiles, imageLookup)
+	require.NoError(t, err)
+	for _, name := range themePresets {
+		require.Contains(t, loaded, name)
+		// the presets keep the colours of the original images
+		assert.False(t, loaded[name].recoloured(), name)
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	testData := []struct {
+		name    string
+		content string
+		theme   *Theme
+	}{
+		{
+			name:    "default patterns",
+			content: `{"test": {"background": ["#000000", "#102030"], "block": "#ff8000", "highlight": "#FFFFFF"}}`,
+			theme: &Theme{
+				Background:        [2]Colour{{0, 0, 0, 0xff}, {0x10, 0x20, 0x30, 0xff}},
+				Block:             Colour{0xff, 0x80, 0x00, 0xff},
+				Highlight:         Colour{0xff, 0xff, 0xff, 0xff},
+				BackgroundPattern: ImagesBg[0],
+				BlockPattern:      ImagesBlock[0],
+			},
+		},
+		{
+			name:    "patterns",
+			content: `{"test": {"background_pattern": "bg2", "block_pattern": "block1"}}`,
+			theme: &Theme{
+				BackgroundPattern: ImagesBg[2],
+				BlockPattern:      ImagesBlock[1],
+			},
+		},
+		{name: "missing colours", content: `{"test": {"background": ["#000000", "#102030"], "block": "#ff8000"}}`},
+		{name: "unknown pattern", content: `{"test": {"background_pattern": "nothing"}}`},
+		{name: "invalid colour", content: `{"test": {"block": "red"}}`},
+		{name: "short colour", content: `{"test": {"block": "#fff"}}`},
+		{name: "unknown field", content: `{"test": {"colour": "#ffffff"}}`},
+	}
+	for _, testItem := range testData {
+		t.Run(testItem.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"themes/test.json": {Data: []byte(testItem.content)},
+			}
+			loaded, err := loadThemes(fsys, imageLookup)
+			if testItem.theme == nil {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testItem.theme, loaded["test"])
+		})
+	}
+}
+
+func TestDuplicateThemes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"themes/a.json": {Data: []byte(`{"test": {}}`)},
+		"themes/b.json": {Data: []byte(`{"test": {}}`)},
+	}
+	_, err := loadThemes(fsys, imageLookup)
+	assert.Error(t, err)
+}
+
+func TestColourRoundTrip(t *testing.T) {
+	colour := Colour{0x12, 0xab, 0xef, 0xff}
+	data, err := json.Marshal(colour)
+	require.NoError(t, err)
+	assert.Equal(t, `"#12abef"`, string(data))
+
+	var decoded Colour
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, colour, decoded)
+	assert.Equal(t, []float32{0x12 / 255.0, 0xab / 255.0, 0xef / 255.0}, colour.vec3())
+}